@@ -0,0 +1,190 @@
+// Package config loads the configuration of the server, client and gateway.
+//
+// A configuration is a struct holding the default values. Load overrides them from, in
+// increasing precedence, a YAML file, environment variables and command-line flags. Every
+// field is named after its yaml tag: field Port in struct Grpc of a config loaded with env
+// prefix BANK_SERVER is read from grpc.port in the file, BANK_SERVER_GRPC_PORT in the
+// environment and -grpc-port on the command line. Struct tags fine tune a field :
+//
+//	flag:"name"    flag name, instead of the one derived from the yaml path
+//	usage:"text"   flag usage
+//	secret:"true"  value is redacted by Redacted
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configFileFlag = "config"
+
+type Options struct {
+	// EnvPrefix prefixes every environment variable, e.g. BANK_SERVER
+	EnvPrefix string
+	// FlagSet receives the config flags, flag.CommandLine when nil
+	FlagSet *flag.FlagSet
+	// Args are the command-line arguments, os.Args[1:] when nil
+	Args []string
+	// DefaultFile is read when no file is set with -config or <EnvPrefix>_CONFIG. It may be
+	// missing.
+	DefaultFile string
+}
+
+// Validator is implemented by configurations checking their own settings after loading.
+type Validator interface {
+	Validate() error
+}
+
+// Load fills cfg, a pointer to a struct holding the default values, from a YAML file,
+// environment variables and command-line flags, then validates it.
+func Load(cfg any, opts Options) error {
+	fields, err := configFields(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	fs := opts.FlagSet
+
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	args := opts.Args
+
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	configFile := fs.String(configFileFlag, "", "YAML configuration file")
+	flagValues := map[string]*flagValue{}
+
+	for _, f := range fields {
+		v := &flagValue{defaultValue: f.String(), isBool: f.value.Kind() == reflect.Bool}
+
+		if f.isSecret() && v.defaultValue != "" {
+			v.defaultValue = redact(v.defaultValue)
+		}
+
+		flagValues[f.flagName()] = v
+		fs.Var(v, f.flagName(), f.usage())
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	file, required := *configFile, true
+
+	if file == "" {
+		file = os.Getenv(envName(opts.EnvPrefix, []string{configFileFlag}))
+	}
+
+	if file == "" {
+		file, required = opts.DefaultFile, false
+	}
+
+	if file != "" {
+		if err := loadFile(cfg, file, required); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		name := envName(opts.EnvPrefix, f.path)
+
+		if value, found := os.LookupEnv(name); found {
+			if err := f.Set(value); err != nil {
+				return fmt.Errorf("invalid environment variable %v : %v", name, err)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if v := flagValues[f.flagName()]; v.isSet {
+			if err := f.Set(v.value); err != nil {
+				return fmt.Errorf("invalid flag -%v : %v", f.flagName(), err)
+			}
+		}
+	}
+
+	if v, ok := cfg.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration : %w", err)
+		}
+	}
+
+	return nil
+}
+
+func loadFile(cfg any, file string, required bool) error {
+	content, err := os.ReadFile(file)
+
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't read configuration file : %v", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("can't parse configuration file %v : %v", file, err)
+	}
+
+	return nil
+}
+
+func envName(prefix string, path []string) string {
+	name := strings.ToUpper(strings.Join(path, "_"))
+
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "_" + name
+}
+
+// flagValue records a flag given on the command line, it is applied after the file and
+// environment variables.
+type flagValue struct {
+	defaultValue string
+	value        string
+	isSet        bool
+	isBool       bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	if v.isSet {
+		return v.value
+	}
+
+	return v.defaultValue
+}
+
+// IsBoolFlag lets a bool field be set with -name, without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	v.isSet = true
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEnvPrefix = "CONFIG_TEST"
+
+var errTestInvalid = errors.New("port out of range")
+
+type testGrpcConfig struct {
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+	Tls     bool          `yaml:"tls"`
+}
+
+type testConfig struct {
+	Name  string         `yaml:"name"`
+	Grpc  testGrpcConfig `yaml:"grpc"`
+	Ratio float64        `yaml:"ratio"`
+	Keys  []string       `yaml:"keys"`
+	Dsn   string         `yaml:"dsn" flag:"db-dsn"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Grpc.Port < 0 || c.Grpc.Port > 65535 {
+		return errTestInvalid
+	}
+
+	return nil
+}
+
+func defaultTestConfig() testConfig {
+	return testConfig{
+		Name: "default",
+		Grpc: testGrpcConfig{Port: 9090, Timeout: time.Second},
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile : %v", err)
+	}
+
+	return path
+}
+
+func loadTestConfig(t *testing.T, opts Options) (testConfig, error) {
+	t.Helper()
+
+	cfg := defaultTestConfig()
+	opts.EnvPrefix = testEnvPrefix
+	opts.FlagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.FlagSet.SetOutput(io.Discard)
+
+	if opts.Args == nil {
+		opts.Args = []string{}
+	}
+
+	err := Load(&cfg, opts)
+
+	return cfg, err
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfigFile(t, "name: file\ngrpc:\n  port: 8000\n  timeout: 5s\n")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want testConfig
+	}{
+		{
+			name: "file",
+			want: testConfig{Name: "file", Grpc: testGrpcConfig{Port: 8000, Timeout: 5 * time.Second}},
+		},
+		{
+			name: "env over file",
+			env:  map[string]string{"CONFIG_TEST_GRPC_PORT": "8001"},
+			want: testConfig{Name: "file", Grpc: testGrpcConfig{Port: 8001, Timeout: 5 * time.Second}},
+		},
+		{
+			name: "flag over env",
+			env:  map[string]string{"CONFIG_TEST_GRPC_PORT": "8001", "CONFIG_TEST_NAME": "env"},
+			args: []string{"-grpc-port", "8002"},
+			want: testConfig{Name: "env", Grpc: testGrpcConfig{Port: 8002, Timeout: 5 * time.Second}},
+		},
+		{
+			name: "flag tag",
+			args: []string{"-db-dsn", "postgres://localhost/bank"},
+			want: testConfig{Name: "file", Grpc: testGrpcConfig{Port: 8000, Timeout: 5 * time.Second},
+				Dsn: "postgres://localhost/bank"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := loadTestConfig(t, Options{Args: append([]string{"-config", file}, tt.args...)})
+
+			if err != nil {
+				t.Fatalf("Load : %v", err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("Load = %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	file := writeConfigFile(t, "name: file\n")
+	missing := filepath.Join(t.TempDir(), "missing.yml")
+
+	t.Run("defaults without file", func(t *testing.T) {
+		cfg, err := loadTestConfig(t, Options{DefaultFile: missing})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if want := defaultTestConfig(); !reflect.DeepEqual(cfg, want) {
+			t.Errorf("Load = %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("default file", func(t *testing.T) {
+		cfg, err := loadTestConfig(t, Options{DefaultFile: file})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if cfg.Name != "file" {
+			t.Errorf("Name = %q, want file", cfg.Name)
+		}
+	})
+
+	t.Run("env file", func(t *testing.T) {
+		t.Setenv("CONFIG_TEST_CONFIG", file)
+
+		cfg, err := loadTestConfig(t, Options{DefaultFile: missing})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if cfg.Name != "file" {
+			t.Errorf("Name = %q, want file", cfg.Name)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := loadTestConfig(t, Options{Args: []string{"-config", missing}}); err == nil {
+			t.Error("Load succeeded with a missing configuration file")
+		}
+	})
+}
+
+func TestLoadCoercion(t *testing.T) {
+	want := testConfig{
+		Name:  "default",
+		Grpc:  testGrpcConfig{Port: 7000, Timeout: 1500 * time.Millisecond, Tls: true},
+		Ratio: 0.5,
+		Keys:  []string{"a", "b"},
+	}
+
+	t.Run("file", func(t *testing.T) {
+		file := writeConfigFile(t,
+			"grpc:\n  port: 7000\n  timeout: 1.5s\n  tls: true\nratio: 0.5\nkeys: [a, b]\n")
+
+		cfg, err := loadTestConfig(t, Options{Args: []string{"-config", file}})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("Load = %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("CONFIG_TEST_GRPC_PORT", "7000")
+		t.Setenv("CONFIG_TEST_GRPC_TIMEOUT", "1500ms")
+		t.Setenv("CONFIG_TEST_GRPC_TLS", "true")
+		t.Setenv("CONFIG_TEST_RATIO", "0.5")
+		t.Setenv("CONFIG_TEST_KEYS", " a, ,b ")
+
+		cfg, err := loadTestConfig(t, Options{})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("Load = %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		cfg, err := loadTestConfig(t, Options{Args: []string{
+			"-grpc-port", "7000", "-grpc-timeout", "1.5s", "-grpc-tls", "-ratio", "0.5", "-keys", "a,b",
+		}})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("Load = %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("false bool flag", func(t *testing.T) {
+		t.Setenv("CONFIG_TEST_GRPC_TLS", "true")
+
+		cfg, err := loadTestConfig(t, Options{Args: []string{"-grpc-tls=false"}})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if cfg.Grpc.Tls {
+			t.Error("Tls = true, want false")
+		}
+	})
+}
+
+func TestLoadInvalidValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "env duration",
+			env:     map[string]string{"CONFIG_TEST_GRPC_TIMEOUT": "soon"},
+			wantErr: "invalid environment variable CONFIG_TEST_GRPC_TIMEOUT",
+		},
+		{
+			name:    "env bool",
+			env:     map[string]string{"CONFIG_TEST_GRPC_TLS": "maybe"},
+			wantErr: "invalid environment variable CONFIG_TEST_GRPC_TLS",
+		},
+		{
+			name:    "flag int",
+			args:    []string{"-grpc-port", "http"},
+			wantErr: "invalid flag -grpc-port",
+		},
+		{
+			name:    "flag float",
+			args:    []string{"-ratio", "half"},
+			wantErr: "invalid flag -ratio",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := loadTestConfig(t, Options{Args: tt.args})
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadUnknownKeys(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		file := writeConfigFile(t, "grpc:\n  port: 7000\n  host: localhost\n")
+
+		_, err := loadTestConfig(t, Options{Args: []string{"-config", file}})
+
+		if err == nil || !strings.Contains(err.Error(), "host") {
+			t.Errorf("Load error = %v, want unknown field host", err)
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		if _, err := loadTestConfig(t, Options{Args: []string{"-grpc-host", "localhost"}}); err == nil {
+			t.Error("Load succeeded with an unknown flag")
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("CONFIG_TEST_GRPC_HOST", "localhost")
+
+		cfg, err := loadTestConfig(t, Options{})
+
+		if err != nil {
+			t.Fatalf("Load : %v", err)
+		}
+
+		if want := defaultTestConfig(); !reflect.DeepEqual(cfg, want) {
+			t.Errorf("Load = %+v, want %+v", cfg, want)
+		}
+	})
+}
+
+func TestLoadValidation(t *testing.T) {
+	t.Setenv("CONFIG_TEST_GRPC_PORT", "70000")
+
+	_, err := loadTestConfig(t, Options{})
+
+	if !errors.Is(err, errTestInvalid) {
+		t.Errorf("Load error = %v, want %v", err, errTestInvalid)
+	}
+}
+
+func TestLoadUnsupportedConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  any
+	}{
+		{name: "not a pointer", cfg: testConfig{}},
+		{name: "unsupported type", cfg: &struct {
+			Ports []int `yaml:"ports"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+			if err := Load(tt.cfg, Options{FlagSet: fs, Args: []string{}}); err == nil {
+				t.Error("Load succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a configurable leaf of a configuration struct.
+type field struct {
+	path  []string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+func configFields(cfg any) ([]field, error) {
+	v := reflect.ValueOf(cfg)
+
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("configuration must be a pointer to struct, got %T", cfg)
+	}
+
+	return structFields(v.Elem(), nil)
+}
+
+func structFields(v reflect.Value, path []string) ([]field, error) {
+	var res []field
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		fieldPath := append(append([]string{}, path...), name)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			nested, err := structFields(fv, fieldPath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, nested...)
+			continue
+		}
+
+		if !isSupported(fv.Type()) {
+			return nil, fmt.Errorf("unsupported configuration type %v on %v", fv.Type(),
+				strings.Join(fieldPath, "."))
+		}
+
+		res = append(res, field{path: fieldPath, value: fv, tag: sf.Tag})
+	}
+
+	return res, nil
+}
+
+func isSupported(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+
+	return false
+}
+
+func (f field) flagName() string {
+	if name := f.tag.Get("flag"); name != "" {
+		return name
+	}
+
+	return strings.ReplaceAll(strings.Join(f.path, "-"), "_", "-")
+}
+
+func (f field) usage() string {
+	if usage := f.tag.Get("usage"); usage != "" {
+		return usage
+	}
+
+	return strings.Join(f.path, ".")
+}
+
+func (f field) isSecret() bool {
+	return f.tag.Get("secret") == "true"
+}
+
+func (f field) String() string {
+	if f.value.Type() == durationType {
+		return time.Duration(f.value.Int()).String()
+	}
+
+	if f.value.Kind() == reflect.Slice {
+		return strings.Join(f.value.Interface().([]string), ",")
+	}
+
+	return fmt.Sprint(f.value.Interface())
+}
+
+// Set parses s into the field. Slices are comma separated.
+func (f field) Set(s string) error {
+	if f.value.Type() == durationType {
+		d, err := time.ParseDuration(s)
+
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+
+		if err != nil {
+			return err
+		}
+
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+
+		if err != nil {
+			return err
+		}
+
+		f.value.SetFloat(n)
+	case reflect.Slice:
+		var items []string
+
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		f.value.Set(reflect.ValueOf(items))
+	}
+
+	return nil
+}
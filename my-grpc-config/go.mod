@@ -0,0 +1,5 @@
+module github.com/timpamungkas/my-grpc-config
+
+go 1.20
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "xxxxx"
+
+// Redacted returns cfg as YAML, with the value of secret fields hidden. For a secret URL only
+// the password is hidden, so the effective host and database can still be checked.
+func Redacted(cfg any) string {
+	v := reflect.ValueOf(cfg)
+
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	clone := reflect.New(v.Type())
+	clone.Elem().Set(v)
+
+	fields, err := configFields(clone.Interface())
+
+	if err != nil {
+		return err.Error()
+	}
+
+	for _, f := range fields {
+		if !f.isSecret() {
+			continue
+		}
+
+		switch {
+		case f.value.Kind() == reflect.String && f.value.String() != "":
+			f.value.SetString(redact(f.value.String()))
+		case f.value.Kind() == reflect.Slice && f.value.Len() > 0:
+			// the clone shares the slice backing array with cfg, replace the slice instead
+			redacted := reflect.MakeSlice(f.value.Type(), f.value.Len(), f.value.Len())
+
+			for i := 0; i < redacted.Len(); i++ {
+				redacted.Index(i).SetString(redactedValue)
+			}
+
+			f.value.Set(redacted)
+		}
+	}
+
+	out, err := yaml.Marshal(clone.Interface())
+
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(out)
+}
+
+func redact(secret string) string {
+	u, err := url.Parse(secret)
+
+	if err != nil || u.User == nil {
+		return redactedValue
+	}
+
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return redactedValue
+	}
+
+	u.User = url.UserPassword(u.User.Username(), redactedValue)
+
+	return u.String()
+}
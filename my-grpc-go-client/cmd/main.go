@@ -10,15 +10,24 @@ import (
 	"github.com/timpamungkas/my-grpc-go-client/internal/adapter/bank"
 	"github.com/timpamungkas/my-grpc-go-client/internal/adapter/hello"
 	"github.com/timpamungkas/my-grpc-go-client/internal/adapter/resiliency"
+	"github.com/timpamungkas/my-grpc-go-client/internal/config"
+	"github.com/timpamungkas/my-grpc-go-client/internal/interceptor"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	resl_proto "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
 
+	myconfig "github.com/timpamungkas/my-grpc-config"
+	mytracing "github.com/timpamungkas/my-grpc-tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/sony/gobreaker"
 	dbank "github.com/timpamungkas/my-grpc-go-client/internal/application/domain/bank"
 )
 
+const serviceName = "my-grpc-go-client"
+
 var cbreaker *gobreaker.CircuitBreaker
 
 func init() {
@@ -46,16 +55,51 @@ func main() {
 	log.SetFlags(0)
 	log.SetOutput(logWriter{})
 
-	var opts []grpc.DialOption
+	cfg, err := config.Load()
 
-	// creds, err := credentials.NewClientTLSFromFile("ssl/ca.crt", "")
+	if err != nil {
+		log.Fatalln("Can't load configuration :", err)
+	}
 
-	// if err != nil {
-	// 	log.Fatalln("Can't create client credentials :", err)
-	// }
+	log.Printf("Effective configuration :\n%v", myconfig.Redacted(cfg))
+
+	opts, err := cfg.DialOptions()
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	shutdownTracing, err := mytracing.Setup(context.Background(), serviceName, cfg.Tracing)
+
+	if err != nil {
+		log.Fatalln("Can't set up tracing :", err)
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			log.Println("Can't flush traces :", err)
+		}
+	}()
+
+	// tracing first, the client starts the root span of each call
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(
+			otelgrpc.UnaryClientInterceptor(),
+			interceptor.MetricsUnaryClientInterceptor(),
+		),
+		grpc.WithChainStreamInterceptor(
+			otelgrpc.StreamClientInterceptor(),
+			interceptor.MetricsStreamClientInterceptor(),
+		),
+	)
+
+	if cfg.MetricsAddress != "" {
+		go serveMetrics(cfg.MetricsAddress)
+	}
 
-	// opts = append(opts, grpc.WithTransportCredentials(creds))
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	// opts = append(opts,
 	// 	grpc.WithUnaryInterceptor(
 	// 		grpc_retry.UnaryClientInterceptor(
@@ -89,7 +133,7 @@ func main() {
 	// 	),
 	// )
 
-	conn, err := grpc.Dial("localhost:9090", opts...)
+	conn, err := grpc.Dial(cfg.Target, opts...)
 
 	if err != nil {
 		log.Fatalln("Can not connect to gRPC server :", err)
@@ -190,9 +234,12 @@ func runSummarizeTransactions(adapter *bank.BankAdapter, acct string, numDummyTr
 		}
 
 		t := dbank.Transaction{
-			Amount:          float64(rand.Intn(500) + 10),
+			Amount:          decimal.NewFromInt(int64(rand.Intn(500) + 10)),
 			TransactionType: ttype,
 			Notes:           fmt.Sprintf("Dummy transaction %v", i),
+			IdempotencyKey:  uuid.New().String(),
+			// spread the transactions over the last few value dates
+			ValueDate: time.Now().AddDate(0, 0, -(i % 4)),
 		}
 
 		tx = append(tx, t)
@@ -210,7 +257,8 @@ func runTransferMultiple(adapter *bank.BankAdapter, fromAcct string, toAcct stri
 			FromAccountNumber: fromAcct,
 			ToAccountNumber:   toAcct,
 			Currency:          "USD",
-			Amount:            float64(rand.Intn(200) + 5),
+			Amount:            decimal.NewFromInt(int64(rand.Intn(200) + 5)),
+			IdempotencyKey:    uuid.New().String(),
 		}
 
 		trf = append(trf, tr)
@@ -236,7 +284,8 @@ func runUnaryResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapter, minDel
 
 func runServerStreamingResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapter,
 	minDelaySecond int32, maxDelaySecond int32, statusCodes []uint32, timeout time.Duration) {
-	ctx, _ := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
 	adapter.ServerStreamingResiliency(ctx, minDelaySecond, maxDelaySecond, statusCodes)
 }
@@ -244,7 +293,8 @@ func runServerStreamingResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapt
 func runClientStreamingResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapter,
 	minDelaySecond int32, maxDelaySecond int32, statusCodes []uint32,
 	count int, timeout time.Duration) {
-	ctx, _ := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
 	adapter.ClientStreamingResiliency(ctx, minDelaySecond, maxDelaySecond, statusCodes, count)
 }
@@ -252,7 +302,8 @@ func runClientStreamingResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapt
 func runBiDirectionalResiliencyWithTimeout(adapter *resiliency.ResiliencyAdapter,
 	minDelaySecond int32, maxDelaySecond int32, statusCodes []uint32,
 	count int, timeout time.Duration) {
-	ctx, _ := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
 	adapter.BiDirectionalResiliency(ctx, minDelaySecond, maxDelaySecond, statusCodes, count)
 }
@@ -4,14 +4,19 @@ import (
 	"context"
 	"io"
 	"log"
+	"time"
 
+	"github.com/shopspring/decimal"
 	dbank "github.com/timpamungkas/my-grpc-go-client/internal/application/domain/bank"
 	"github.com/timpamungkas/my-grpc-go-client/internal/port"
 	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/genproto/googleapis/type/money"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type BankAdapter struct {
@@ -26,6 +31,35 @@ func NewBankAdapter(conn *grpc.ClientConn) (*BankAdapter, error) {
 	}, nil
 }
 
+func toMoney(d decimal.Decimal, currency string) *money.Money {
+	units := d.Truncate(0)
+
+	return &money.Money{
+		CurrencyCode: currency,
+		Units:        units.IntPart(),
+		Nanos:        int32(d.Sub(units).Shift(9).IntPart()),
+	}
+}
+
+// toDatetime converts t to google.type.DateTime with the UTC offset of its location, so the
+// server reads the same instant.
+func toDatetime(t time.Time) *datetime.DateTime {
+	_, offset := t.Zone()
+
+	return &datetime.DateTime{
+		Year:    int32(t.Year()),
+		Month:   int32(t.Month()),
+		Day:     int32(t.Day()),
+		Hours:   int32(t.Hour()),
+		Minutes: int32(t.Minute()),
+		Seconds: int32(t.Second()),
+		Nanos:   int32(t.Nanosecond()),
+		TimeOffset: &datetime.DateTime_UtcOffset{
+			UtcOffset: durationpb.New(time.Duration(offset) * time.Second),
+		},
+	}
+}
+
 func (a *BankAdapter) GetCurrentBalance(ctx context.Context, acct string) (
 	*bank.CurrentBalanceResponse, error) {
 	bankRequest := &bank.CurrentBalanceRequest{
@@ -70,7 +104,7 @@ func (a *BankAdapter) FetchExchangeRates(ctx context.Context, fromCur string, to
 		}
 
 		log.Printf("Rate at %v from %v to %v is %v\n", rate.Timestamp,
-			rate.FromCurrency, rate.ToCurrency, rate.Rate)
+			rate.FromCurrency, rate.ToCurrency, rate.RateDecimal)
 	}
 }
 
@@ -92,10 +126,15 @@ func (a *BankAdapter) SummarizeTransactions(ctx context.Context, acct string,
 		}
 
 		bankRequest := &bank.Transaction{
-			AccountNumber: acct,
-			Type:          ttype,
-			Amount:        t.Amount,
-			Notes:         t.Notes,
+			AccountNumber:  acct,
+			Type:           ttype,
+			Amount:         toMoney(t.Amount, ""),
+			Notes:          t.Notes,
+			IdempotencyKey: t.IdempotencyKey,
+		}
+
+		if !t.ValueDate.IsZero() {
+			bankRequest.Timestamp = toDatetime(t.ValueDate)
 		}
 
 		txStream.Send(bankRequest)
@@ -126,7 +165,8 @@ func (a *BankAdapter) TransferMultiple(ctx context.Context, trf []dbank.Transfer
 				FromAccountNumber: tt.FromAccountNumber,
 				ToAccountNumber:   tt.ToAccountNumber,
 				Currency:          tt.Currency,
-				Amount:            tt.Amount,
+				Amount:            toMoney(tt.Amount, tt.Currency),
+				IdempotencyKey:    tt.IdempotencyKey,
 			}
 
 			trfStream.Send(req)
@@ -147,7 +187,8 @@ func (a *BankAdapter) TransferMultiple(ctx context.Context, trf []dbank.Transfer
 				handleTransferErrorGrpc(err)
 				break
 			} else {
-				log.Printf("Transfer status %v on %v\n", res.Status, res.Timestamp)
+				log.Printf("Transfer status %v on %v, exchange rate %v\n", res.Status, res.Timestamp,
+					res.ExchangeRate)
 			}
 		}
 
@@ -168,6 +209,10 @@ func handleTransferErrorGrpc(err error) {
 			for _, violation := range t.GetViolations() {
 				log.Println("[VIOLATION]", violation)
 			}
+		case *errdetails.QuotaFailure:
+			for _, violation := range t.GetViolations() {
+				log.Println("[QUOTA]", violation)
+			}
 		case *errdetails.ErrorInfo:
 			log.Printf("Error on : %v, with reason %v\n", t.Domain, t.Reason)
 			for k, v := range t.GetMetadata() {
@@ -1,19 +1,29 @@
 package bank
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 const (
 	TransactionTypeIn  string = "IN"
 	TransactionTypeOut string = "OUT"
 )
 
 type Transaction struct {
-	Amount          float64
+	Amount          decimal.Decimal
 	TransactionType string
 	Notes           string
+	IdempotencyKey  string
+	// ValueDate backdates or future dates the transaction, zero for the current time
+	ValueDate time.Time
 }
 
 type TransferTransaction struct {
 	FromAccountNumber string
 	ToAccountNumber   string
 	Currency          string
-	Amount            float64
+	Amount            decimal.Decimal
+	IdempotencyKey    string
 }
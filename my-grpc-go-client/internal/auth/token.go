@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+const authorizationHeader = "authorization"
+
+// tokenCredentials sends a bearer token with every RPC. A token file is read again on each
+// RPC, so a token refreshed by another process is picked up without restarting.
+type tokenCredentials struct {
+	token      string
+	tokenFile  string
+	requireTls bool
+}
+
+// NewTokenCredentials returns per-RPC credentials sending token, or the content of tokenFile
+// when token is empty, as a bearer token. Unless requireTls is false, the token is only sent
+// over TLS connections.
+func NewTokenCredentials(token string, tokenFile string, requireTls bool) credentials.PerRPCCredentials {
+	return &tokenCredentials{
+		token:      token,
+		tokenFile:  tokenFile,
+		requireTls: requireTls,
+	}
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token := c.token
+
+	if token == "" {
+		data, err := os.ReadFile(c.tokenFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't read token file : %v", err)
+		}
+
+		token = strings.TrimSpace(string(data))
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("empty bearer token")
+	}
+
+	return map[string]string{
+		authorizationHeader: "Bearer " + token,
+	}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return c.requireTls
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	myconfig "github.com/timpamungkas/my-grpc-config"
+	"github.com/timpamungkas/my-grpc-go-client/internal/auth"
+	mytls "github.com/timpamungkas/my-grpc-tls"
+	mytracing "github.com/timpamungkas/my-grpc-tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	envPrefix   = "GRPC_CLIENT"
+	defaultFile = "config.yml"
+)
+
+type Config struct {
+	Target         string             `yaml:"target" usage:"gRPC server address (host:port)"`
+	Tls            mytls.ClientConfig `yaml:"tls"`
+	Auth           AuthConfig         `yaml:"auth"`
+	MetricsAddress string             `yaml:"metrics_address" usage:"HTTP address serving client Prometheus metrics on /metrics, empty to disable"`
+	Tracing        mytracing.Config   `yaml:"tracing"`
+}
+
+// AuthConfig holds the bearer token sent with every RPC, required by servers enforcing
+// authentication.
+type AuthConfig struct {
+	Token         string `yaml:"token" secret:"true" usage:"bearer token sent with every RPC"`
+	TokenFile     string `yaml:"token_file" usage:"file holding the bearer token, read on every RPC"`
+	AllowInsecure bool   `yaml:"allow_insecure" usage:"send the bearer token without TLS, for local tests only"`
+}
+
+func Default() Config {
+	return Config{
+		Target: "localhost:9090",
+		Tls: mytls.ClientConfig{
+			CaFile: "ssl/ca.crt",
+		},
+		Tracing: mytracing.DefaultConfig(),
+	}
+}
+
+// Load returns the default configuration overridden by config.yml (or the -config file), the
+// GRPC_CLIENT_* environment variables and command-line flags.
+func Load() (Config, error) {
+	cfg := Default()
+
+	err := myconfig.Load(&cfg, myconfig.Options{
+		EnvPrefix:   envPrefix,
+		DefaultFile: defaultFile,
+	})
+
+	return cfg, err
+}
+
+func (c *Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Target); err != nil {
+		return fmt.Errorf("target %q : %v", c.Target, err)
+	}
+
+	if err := c.Tls.Validate(); err != nil {
+		return fmt.Errorf("tls : %v", err)
+	}
+
+	if c.Auth.Token != "" && c.Auth.TokenFile != "" {
+		return fmt.Errorf("auth.token and auth.token_file are exclusive")
+	}
+
+	if c.Auth.TokenFile != "" {
+		if _, err := os.Stat(c.Auth.TokenFile); err != nil {
+			return fmt.Errorf("auth.token_file : %v", err)
+		}
+	}
+
+	if (c.Auth.Token != "" || c.Auth.TokenFile != "") && !c.Tls.Enabled && !c.Auth.AllowInsecure {
+		return fmt.Errorf("bearer token requires tls.enabled, or auth.allow_insecure for local tests")
+	}
+
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	if c.MetricsAddress != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddress); err != nil {
+			return fmt.Errorf("metrics_address %q : %v", c.MetricsAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// DialOptions returns the transport credentials matching the TLS settings, and the per-RPC
+// bearer token credentials when a token is set.
+func (c Config) DialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if c.Tls.Enabled {
+		tlsConfig, err := mytls.NewClientTLSConfig(c.Tls)
+
+		if err != nil {
+			return nil, fmt.Errorf("can't create client credentials : %v", err)
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if c.Auth.Token != "" || c.Auth.TokenFile != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			auth.NewTokenCredentials(c.Auth.Token, c.Auth.TokenFile, !c.Auth.AllowInsecure)))
+	}
+
+	return opts, nil
+}
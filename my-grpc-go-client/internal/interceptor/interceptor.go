@@ -58,7 +58,8 @@ func BasicUnaryClientInterceptor() grpc.UnaryClientInterceptor {
 func TimeoutUnaryClientInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
 		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		newCtx, _ := context.WithTimeout(ctx, timeout)
+		newCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
 
 		return invoker(newCtx, method, req, reply, cc, opts...)
 	}
@@ -128,8 +129,31 @@ func (s *InterceptedClientStream) RecvMsg(msg interface{}) error {
 func TimeoutStreamClientInterceptor(timeout time.Duration) grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
 		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-		newCtx, _ := context.WithTimeout(ctx, timeout)
+		newCtx, cancel := context.WithTimeout(ctx, timeout)
 
-		return streamer(newCtx, desc, cc, method, opts...)
+		clientStream, err := streamer(newCtx, desc, cc, method, opts...)
+
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		return &timeoutClientStream{ClientStream: clientStream, cancel: cancel}, nil
+	}
+}
+
+// timeoutClientStream releases the timeout context once the stream is over.
+type timeoutClientStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+func (s *timeoutClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err != nil {
+		s.cancel()
 	}
+
+	return err
 }
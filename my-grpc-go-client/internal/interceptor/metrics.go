@@ -0,0 +1,180 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	unary           = "unary"
+	clientStreaming = "client_stream"
+	serverStreaming = "server_stream"
+	bidiStreaming   = "bidi_stream"
+)
+
+// client counterparts of the server grpc_server_* metrics
+var (
+	grpcClientStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_started_total",
+		Help: "RPCs started by the client.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	grpcClientHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "RPCs completed by the client, by status code.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+
+	grpcClientHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "RPC time seen by the client, up to the end of the stream for streaming RPCs.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	grpcClientMsgReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_msg_received_total",
+		Help: "Messages received by the client.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	grpcClientMsgSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_msg_sent_total",
+		Help: "Messages sent by the client.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+)
+
+// MetricsUnaryClientInterceptor counts unary RPCs and their duration.
+func MetricsUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		service, name := splitMethodName(method)
+		start := time.Now()
+
+		grpcClientStarted.WithLabelValues(unary, service, name).Inc()
+		grpcClientMsgSent.WithLabelValues(unary, service, name).Inc()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		if err == nil {
+			grpcClientMsgReceived.WithLabelValues(unary, service, name).Inc()
+		}
+
+		grpcClientHandled.WithLabelValues(unary, service, name, status.Code(err).String()).Inc()
+		grpcClientHandlingSeconds.WithLabelValues(unary, service, name).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// MetricsStreamClientInterceptor counts streaming RPCs, their messages and their duration. A
+// stream is done when a receive fails, or after the single response of a client streaming RPC.
+func MetricsStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		service, name := splitMethodName(method)
+		kind := streamType(desc)
+		s := &metricsClientStream{
+			start:    time.Now(),
+			kind:     kind,
+			service:  service,
+			method:   name,
+			unaryRes: !desc.ServerStreams,
+		}
+
+		grpcClientStarted.WithLabelValues(kind, service, name).Inc()
+
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+
+		if err != nil {
+			s.done(err)
+			return nil, err
+		}
+
+		s.ClientStream = clientStream
+
+		return s, nil
+	}
+}
+
+type metricsClientStream struct {
+	grpc.ClientStream
+	start    time.Time
+	kind     string
+	service  string
+	method   string
+	unaryRes bool
+	doneOnce sync.Once
+}
+
+func (s *metricsClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+
+	if err == nil {
+		grpcClientMsgSent.WithLabelValues(s.kind, s.service, s.method).Inc()
+	}
+
+	return err
+}
+
+func (s *metricsClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	switch {
+	case err == nil:
+		grpcClientMsgReceived.WithLabelValues(s.kind, s.service, s.method).Inc()
+
+		if s.unaryRes {
+			s.done(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.done(nil)
+	default:
+		s.done(err)
+	}
+
+	return err
+}
+
+func (s *metricsClientStream) done(err error) {
+	s.doneOnce.Do(func() {
+		code := codes.OK
+
+		if err != nil {
+			code = status.Code(err)
+		}
+
+		grpcClientHandled.WithLabelValues(s.kind, s.service, s.method, code.String()).Inc()
+		grpcClientHandlingSeconds.WithLabelValues(s.kind, s.service, s.method).
+			Observe(time.Since(s.start).Seconds())
+	})
+}
+
+func streamType(desc *grpc.StreamDesc) string {
+	switch {
+	case desc.ClientStreams && desc.ServerStreams:
+		return bidiStreaming
+	case desc.ClientStreams:
+		return clientStreaming
+	default:
+		return serverStreaming
+	}
+}
+
+// splitMethodName splits /package.Service/Method into package.Service and Method.
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", fullMethod
+}
@@ -18,4 +18,6 @@ type BankClientPort interface {
 		opts ...grpc.CallOption) (bank.BankService_TransferMultipleClient, error)
 	CreateAccount(ctx context.Context, in *bank.CreateAccountRequest,
 		opts ...grpc.CallOption) (*bank.CreateAccountResponse, error)
+	ListTransactions(ctx context.Context, in *bank.ListTransactionsRequest,
+		opts ...grpc.CallOption) (*bank.ListTransactionsResponse, error)
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-go-server/internal/adapter/exchangerate"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+)
+
+const exchangeRateHttpTimeout = 10 * time.Second
+
+func newExchangeRateProvider(cfg config.ExchangeRateConfig) (port.ExchangeRateProviderPort, error) {
+	switch cfg.Provider {
+	case config.ExchangeRateProviderRandom:
+		return exchangerate.NewRandomProvider(2000, 2299), nil
+	case config.ExchangeRateProviderFile:
+		return exchangerate.NewFileProvider(cfg.File)
+	case config.ExchangeRateProviderHttp:
+		return exchangerate.NewHttpProvider(cfg.Url, exchangeRateHttpTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider %v", cfg.Provider)
+	}
+}
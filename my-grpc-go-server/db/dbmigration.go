@@ -2,6 +2,8 @@ package dbmigration
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 
 	migrate "github.com/golang-migrate/migrate/v4"
@@ -10,23 +12,35 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
-func Migrate(conn *sql.DB) {
+// Migrate applies the pending migrations. The server keeps running when it fails, the error
+// is reported through the bank health status.
+func Migrate(conn *sql.DB, migrationsPath string) error {
 	log.Println("Database migration start")
 
-	driver, _ := postgres.WithInstance(conn, &postgres.Config{})
-	m, err := migrate.NewWithDatabaseInstance("file://db/migrations", "postgres", driver)
+	driver, err := postgres.WithInstance(conn, &postgres.Config{})
 
 	if err != nil {
 		log.Println("Database migration failed :", err)
+		return fmt.Errorf("can't create migration driver : %v", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(migrationsPath, "postgres", driver)
+
+	if err != nil {
+		log.Println("Database migration failed :", err)
+		return fmt.Errorf("can't read migrations from %v : %v", migrationsPath, err)
 	}
 
 	// if err := m.Down(); err != nil {
 	// 	log.Println("Database migration (down) failed :", err)
 	// }
 
-	if err := m.Up(); err != nil {
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		log.Println("Database migration (up) failed :", err)
+		return fmt.Errorf("migration (up) failed : %v", err)
 	}
 
 	log.Println("Database migration end")
+
+	return nil
 }
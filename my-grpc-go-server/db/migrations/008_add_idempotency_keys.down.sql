@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS bank_transactions_idempotency_key_idx;
+
+ALTER TABLE bank_transactions DROP COLUMN IF EXISTS idempotency_key;
+
+DROP INDEX IF EXISTS bank_transfers_idempotency_key_idx;
+
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1 @@
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS failure_reason;
@@ -0,0 +1 @@
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS failure_reason TEXT;
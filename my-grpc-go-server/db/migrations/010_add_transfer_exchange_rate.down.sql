@@ -0,0 +1,3 @@
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS exchange_rate_uuid;
+
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS exchange_rate;
@@ -0,0 +1,4 @@
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20,10) NOT NULL DEFAULT 1;
+
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS exchange_rate_uuid UUID
+  REFERENCES bank_exchange_rates;
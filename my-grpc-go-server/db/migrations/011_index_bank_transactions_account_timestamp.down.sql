@@ -0,0 +1 @@
+DROP INDEX IF EXISTS bank_transactions_account_timestamp_idx;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bank_accounts_owner_subject_idx;
+
+ALTER TABLE bank_accounts DROP COLUMN IF EXISTS owner_subject;
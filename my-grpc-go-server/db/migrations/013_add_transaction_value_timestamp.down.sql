@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS bank_transactions_account_timestamp_idx
+  ON bank_transactions (account_uuid, transaction_timestamp DESC, transaction_uuid DESC);
+
+DROP INDEX IF EXISTS bank_transactions_account_value_timestamp_idx;
+
+ALTER TABLE bank_transactions DROP COLUMN IF EXISTS value_timestamp;
@@ -0,0 +1,11 @@
+ALTER TABLE bank_transactions ADD COLUMN IF NOT EXISTS value_timestamp TIMESTAMPTZ;
+
+UPDATE bank_transactions SET value_timestamp = transaction_timestamp WHERE value_timestamp IS NULL;
+
+ALTER TABLE bank_transactions ALTER COLUMN value_timestamp SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS bank_transactions_account_value_timestamp_idx
+  ON bank_transactions (account_uuid, value_timestamp DESC, transaction_uuid DESC);
+
+-- ListTransactions pages by value date now
+DROP INDEX IF EXISTS bank_transactions_account_timestamp_idx;
@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bank_account_status_history CASCADE;
+
+ALTER TABLE bank_accounts DROP COLUMN IF EXISTS status;
@@ -0,0 +1,15 @@
+ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE'
+  CONSTRAINT bank_accounts_status_check CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
+
+CREATE TABLE IF NOT EXISTS bank_account_status_history(
+    history_uuid            UUID            PRIMARY KEY,
+    account_uuid            UUID            NOT NULL REFERENCES bank_accounts,
+    previous_status         VARCHAR(20)     NOT NULL,
+    new_status              VARCHAR(20)     NOT NULL,
+    reason                  TEXT            NOT NULL,
+    changed_by              VARCHAR(255),
+    changed_at              TIMESTAMPTZ     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS bank_account_status_history_account_idx
+  ON bank_account_status_history (account_uuid, changed_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bank_transactions_account_out_timestamp_idx;
+
+DROP TABLE IF EXISTS bank_account_policies CASCADE;
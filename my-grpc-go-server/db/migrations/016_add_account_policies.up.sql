@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS bank_account_policies(
+    account_uuid            UUID            PRIMARY KEY REFERENCES bank_accounts,
+    overdraft_limit         NUMERIC(15,2)   NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0),
+    max_transaction_amount  NUMERIC(15,2)   NOT NULL DEFAULT 0 CHECK (max_transaction_amount >= 0),
+    daily_outgoing_limit    NUMERIC(15,2)   NOT NULL DEFAULT 0 CHECK (daily_outgoing_limit >= 0),
+    monthly_outgoing_limit  NUMERIC(15,2)   NOT NULL DEFAULT 0 CHECK (monthly_outgoing_limit >= 0),
+    updated_by              VARCHAR(255),
+    created_at              TIMESTAMPTZ     NOT NULL,
+    updated_at              TIMESTAMPTZ     NOT NULL
+);
+
+-- daily and monthly outgoing totals are summed by booking timestamp
+CREATE INDEX IF NOT EXISTS bank_transactions_account_out_timestamp_idx
+  ON bank_transactions (account_uuid, transaction_timestamp) WHERE transaction_type = 'OUT';
@@ -0,0 +1,3 @@
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS failure_detail;
+
+ALTER TABLE bank_transfers DROP COLUMN IF EXISTS failure_kind;
@@ -0,0 +1,4 @@
+-- a replayed failed transfer returns the error it failed with, rebuilt from the kind and detail
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS failure_kind VARCHAR(40);
+
+ALTER TABLE bank_transfers ADD COLUMN IF NOT EXISTS failure_detail JSONB;
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const authorizationHeader = "authorization"
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512", "EdDSA"}
+
+// Authenticator identifies the caller of an RPC from its bearer token, verified against the
+// keys of a local JWKS file, or from its verified client certificate.
+type Authenticator struct {
+	cfg    config.AuthConfig
+	keys   *jwks
+	admins map[string]bool
+}
+
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		cfg:    cfg,
+		admins: map[string]bool{},
+	}
+
+	for _, s := range cfg.AdminSubjects {
+		a.admins[s] = true
+	}
+
+	if cfg.JwksFile != "" {
+		keys, err := newJwks(cfg.JwksFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		a.keys = keys
+	}
+
+	return a, nil
+}
+
+// Authenticate returns the caller principal. A bearer token takes precedence over the client
+// certificate; an invalid token fails even when a valid certificate is presented.
+func (a *Authenticator) Authenticate(ctx context.Context) (dauth.Principal, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationHeader); len(values) > 0 {
+			return a.authenticateToken(values[0])
+		}
+	}
+
+	if a.cfg.Mtls {
+		if p, ok := a.authenticateCertificate(ctx); ok {
+			return p, nil
+		}
+	}
+
+	return dauth.Principal{}, dauth.ErrUnauthenticated
+}
+
+func (a *Authenticator) authenticateToken(header string) (dauth.Principal, error) {
+	scheme, token, found := strings.Cut(header, " ")
+
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return dauth.Principal{}, fmt.Errorf("%w : authorization must be a bearer token",
+			dauth.ErrInvalidCredentials)
+	}
+
+	if a.keys == nil {
+		return dauth.Principal{}, fmt.Errorf("%w : bearer tokens are not accepted", dauth.ErrInvalidCredentials)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+	}
+
+	if a.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.cfg.Issuer))
+	}
+
+	if a.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.cfg.Audience))
+	}
+
+	claims := jwt.RegisteredClaims{}
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		return a.keys.key(kid)
+	}, opts...)
+
+	if err != nil {
+		return dauth.Principal{}, fmt.Errorf("%w : %v", dauth.ErrInvalidCredentials, err)
+	}
+
+	if claims.ExpiresAt == nil {
+		return dauth.Principal{}, fmt.Errorf("%w : token has no expiration", dauth.ErrInvalidCredentials)
+	}
+
+	if claims.Subject == "" {
+		return dauth.Principal{}, fmt.Errorf("%w : token has no subject", dauth.ErrInvalidCredentials)
+	}
+
+	if strings.HasPrefix(claims.Subject, dauth.CertificateSubjectPrefix) {
+		return dauth.Principal{}, fmt.Errorf("%w : token subject %q is a certificate identity",
+			dauth.ErrInvalidCredentials, claims.Subject)
+	}
+
+	return a.principal(claims.Subject, dauth.MethodJwt), nil
+}
+
+// authenticateCertificate returns the identity of a client certificate verified by the TLS
+// handshake : its first URI SAN, e.g. a SPIFFE id, or else its common name, prefixed with
+// dauth.CertificateSubjectPrefix.
+func (a *Authenticator) authenticateCertificate(ctx context.Context) (dauth.Principal, bool) {
+	p, ok := peer.FromContext(ctx)
+
+	if !ok {
+		return dauth.Principal{}, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return dauth.Principal{}, false
+	}
+
+	leaf := tlsInfo.State.VerifiedChains[0][0]
+
+	if len(leaf.URIs) > 0 {
+		return a.principal(dauth.CertificateSubjectPrefix+leaf.URIs[0].String(), dauth.MethodMtls), true
+	}
+
+	if leaf.Subject.CommonName != "" {
+		return a.principal(dauth.CertificateSubjectPrefix+leaf.Subject.CommonName, dauth.MethodMtls), true
+	}
+
+	return dauth.Principal{}, false
+}
+
+func (a *Authenticator) principal(subject string, method string) dauth.Principal {
+	return dauth.Principal{
+		Subject: subject,
+		Method:  method,
+		Admin:   a.admins[subject],
+	}
+}
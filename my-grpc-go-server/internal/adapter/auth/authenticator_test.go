@@ -0,0 +1,347 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-tls/certgen"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func mustRsaKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("GenerateKey : %v", err)
+	}
+
+	return key
+}
+
+// writeJwks writes the public keys, by key id, to a JWKS file and returns its path.
+func writeJwks(t *testing.T, file string, keys map[string]*rsa.PrivateKey) string {
+	t.Helper()
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	data, err := json.Marshal(set)
+
+	if err != nil {
+		t.Fatalf("Marshal : %v", err)
+	}
+
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatalf("WriteFile : %v", err)
+	}
+
+	return file
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+
+	if err != nil {
+		t.Fatalf("SignedString : %v", err)
+	}
+
+	return signed
+}
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func validClaims(subject string) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    "https://issuer.test",
+		Audience:  jwt.ClaimStrings{"bank"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	key, otherKey := mustRsaKey(t), mustRsaKey(t)
+	a, err := NewAuthenticator(config.AuthConfig{
+		JwksFile:      writeJwks(t, filepath.Join(t.TempDir(), "jwks.json"), map[string]*rsa.PrivateKey{"k1": key}),
+		Issuer:        "https://issuer.test",
+		Audience:      "bank",
+		AdminSubjects: []string{"admin"},
+	})
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	expired := validClaims("alice")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noExpiry := validClaims("alice")
+	noExpiry.ExpiresAt = nil
+	notYetValid := validClaims("alice")
+	notYetValid.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	wrongIssuer := validClaims("alice")
+	wrongIssuer.Issuer = "https://other.test"
+	wrongAudience := validClaims("alice")
+	wrongAudience.Audience = jwt.ClaimStrings{"other"}
+
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims("alice")).SignedString([]byte("secret"))
+
+	if err != nil {
+		t.Fatalf("SignedString : %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    dauth.Principal
+		wantErr error
+	}{
+		{"valid", bearerContext(signToken(t, key, "k1", validClaims("alice"))),
+			dauth.Principal{Subject: "alice", Method: dauth.MethodJwt}, nil},
+		{"admin", bearerContext(signToken(t, key, "k1", validClaims("admin"))),
+			dauth.Principal{Subject: "admin", Method: dauth.MethodJwt, Admin: true}, nil},
+		{"expired", bearerContext(signToken(t, key, "k1", expired)), dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"no expiry", bearerContext(signToken(t, key, "k1", noExpiry)), dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"not yet valid", bearerContext(signToken(t, key, "k1", notYetValid)), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"wrong key", bearerContext(signToken(t, otherKey, "k1", validClaims("alice"))), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"unknown kid", bearerContext(signToken(t, key, "k2", validClaims("alice"))), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"no kid", bearerContext(signToken(t, key, "", validClaims("alice"))), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"hmac", bearerContext(hmacToken), dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"wrong issuer", bearerContext(signToken(t, key, "k1", wrongIssuer)), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"wrong audience", bearerContext(signToken(t, key, "k1", wrongAudience)), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"no subject", bearerContext(signToken(t, key, "k1", validClaims(""))), dauth.Principal{},
+			dauth.ErrInvalidCredentials},
+		{"certificate subject", bearerContext(signToken(t, key, "k1", validClaims("x509:alice"))),
+			dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"not bearer", metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization",
+			"Basic YWxpY2U6cGFzc3dvcmQ=")), dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"malformed", bearerContext("not-a-jwt"), dauth.Principal{}, dauth.ErrInvalidCredentials},
+		{"no credentials", context.Background(), dauth.Principal{}, dauth.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authenticate(tt.ctx)
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Authenticate error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authenticate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateTokenWithoutJwks(t *testing.T) {
+	a, err := NewAuthenticator(config.AuthConfig{Mtls: true})
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	_, err = a.Authenticate(bearerContext(signToken(t, mustRsaKey(t), "k1", validClaims("alice"))))
+
+	if !errors.Is(err, dauth.ErrInvalidCredentials) {
+		t.Errorf("Authenticate error = %v, want %v", err, dauth.ErrInvalidCredentials)
+	}
+}
+
+func TestJwksRotation(t *testing.T) {
+	oldKey, newKey := mustRsaKey(t), mustRsaKey(t)
+	file := writeJwks(t, filepath.Join(t.TempDir(), "jwks.json"), map[string]*rsa.PrivateKey{"old": oldKey})
+
+	a, err := NewAuthenticator(config.AuthConfig{JwksFile: file})
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	token := signToken(t, newKey, "new", validClaims("alice"))
+
+	if _, err := a.Authenticate(bearerContext(token)); !errors.Is(err, dauth.ErrInvalidCredentials) {
+		t.Fatalf("Authenticate before rotation error = %v, want %v", err, dauth.ErrInvalidCredentials)
+	}
+
+	writeJwks(t, file, map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey})
+
+	// the file system clock may not tick between both writes
+	later := time.Now().Add(time.Minute)
+
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatalf("Chtimes : %v", err)
+	}
+
+	if _, err := a.Authenticate(bearerContext(token)); err != nil {
+		t.Errorf("Authenticate after rotation : %v", err)
+	}
+}
+
+// testCertificates issues client certificates from a throwaway CA.
+type testCertificates struct {
+	ca *certgen.CA
+}
+
+func newTestCertificates(t *testing.T) *testCertificates {
+	t.Helper()
+
+	ca, err := certgen.NewCA("Test CA", time.Hour)
+
+	if err != nil {
+		t.Fatalf("NewCA : %v", err)
+	}
+
+	return &testCertificates{ca: ca}
+}
+
+func (c *testCertificates) issue(t *testing.T, commonName string, uris ...string) *x509.Certificate {
+	t.Helper()
+
+	opts := certgen.Options{CommonName: commonName, Validity: time.Hour}
+
+	for _, u := range uris {
+		parsed, err := url.Parse(u)
+
+		if err != nil {
+			t.Fatalf("url.Parse : %v", err)
+		}
+
+		opts.URIs = append(opts.URIs, parsed)
+	}
+
+	cert, err := c.ca.Issue(opts)
+
+	if err != nil {
+		t.Fatalf("Issue : %v", err)
+	}
+
+	return cert.Cert
+}
+
+// tlsContext is the context of an RPC over a TLS connection presenting cert, as verified by the
+// handshake when verified is set.
+func tlsContext(ctx context.Context, cert *x509.Certificate, verified bool) context.Context {
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	if verified {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestAuthenticateCertificate(t *testing.T) {
+	certs := newTestCertificates(t)
+	key := mustRsaKey(t)
+	a, err := NewAuthenticator(config.AuthConfig{
+		JwksFile:      writeJwks(t, filepath.Join(t.TempDir(), "jwks.json"), map[string]*rsa.PrivateKey{"k1": key}),
+		Mtls:          true,
+		AdminSubjects: []string{"x509:spiffe://bank/admin", "alice"},
+	})
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    dauth.Principal
+		wantErr error
+	}{
+		{"uri san", tlsContext(context.Background(), certs.issue(t, "client", "spiffe://bank/client"), true),
+			dauth.Principal{Subject: "x509:spiffe://bank/client", Method: dauth.MethodMtls}, nil},
+		{"first uri san", tlsContext(context.Background(),
+			certs.issue(t, "client", "spiffe://bank/first", "spiffe://bank/second"), true),
+			dauth.Principal{Subject: "x509:spiffe://bank/first", Method: dauth.MethodMtls}, nil},
+		{"common name", tlsContext(context.Background(), certs.issue(t, "bob"), true),
+			dauth.Principal{Subject: "x509:bob", Method: dauth.MethodMtls}, nil},
+		{"admin", tlsContext(context.Background(), certs.issue(t, "admin", "spiffe://bank/admin"), true),
+			dauth.Principal{Subject: "x509:spiffe://bank/admin", Method: dauth.MethodMtls, Admin: true}, nil},
+		// a certificate named after the token admin alice is not alice
+		{"common name of a token subject", tlsContext(context.Background(), certs.issue(t, "alice"), true),
+			dauth.Principal{Subject: "x509:alice", Method: dauth.MethodMtls}, nil},
+		{"no identity", tlsContext(context.Background(), certs.issue(t, ""), true), dauth.Principal{},
+			dauth.ErrUnauthenticated},
+		{"unverified", tlsContext(context.Background(), certs.issue(t, "bob"), false), dauth.Principal{},
+			dauth.ErrUnauthenticated},
+		{"token first", tlsContext(bearerContext(signToken(t, key, "k1", validClaims("carol"))),
+			certs.issue(t, "bob"), true), dauth.Principal{Subject: "carol", Method: dauth.MethodJwt}, nil},
+		{"invalid token with certificate", tlsContext(bearerContext("not-a-jwt"), certs.issue(t, "bob"), true),
+			dauth.Principal{}, dauth.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authenticate(tt.ctx)
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Authenticate error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authenticate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateCertificateWithoutMtls(t *testing.T) {
+	a, err := NewAuthenticator(config.AuthConfig{})
+
+	if err != nil {
+		t.Fatalf("NewAuthenticator : %v", err)
+	}
+
+	_, err = a.Authenticate(tlsContext(context.Background(), newTestCertificates(t).issue(t, "bob"), true))
+
+	if !errors.Is(err, dauth.ErrUnauthenticated) {
+		t.Errorf("Authenticate error = %v, want %v", err, dauth.ErrUnauthenticated)
+	}
+}
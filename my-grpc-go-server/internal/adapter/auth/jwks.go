@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwks holds the public keys of a local JWKS file, by key id. The file is read again when a
+// token is signed with an unknown key id and the file changed since, so rotated keys are
+// picked up without a restart.
+type jwks struct {
+	file    string
+	mu      sync.Mutex
+	modTime time.Time
+	keys    map[string]crypto.PublicKey
+}
+
+func newJwks(file string) (*jwks, error) {
+	k := &jwks{file: file}
+
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (k *jwks) key(kid string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, found := k.keys[kid]; found {
+		return key, nil
+	}
+
+	if info, err := os.Stat(k.file); err == nil && info.ModTime().After(k.modTime) {
+		if err := k.load(); err != nil {
+			return nil, err
+		}
+
+		if key, found := k.keys[kid]; found {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// load reads the JWKS file, it must be called with the lock held or before sharing k.
+func (k *jwks) load() error {
+	info, err := os.Stat(k.file)
+
+	if err != nil {
+		return fmt.Errorf("can't read JWKS file : %v", err)
+	}
+
+	data, err := os.ReadFile(k.file)
+
+	if err != nil {
+		return fmt.Errorf("can't read JWKS file : %v", err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("can't parse JWKS file %v : %v", k.file, err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+
+		if err != nil {
+			return fmt.Errorf("invalid key %q in JWKS file %v : %v", jwk.Kid, k.file, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("no signing key in JWKS file %v", k.file)
+	}
+
+	k.keys = keys
+	k.modTime = info.ModTime()
+
+	return nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point not on curve %v", jwk.Crv)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -1,17 +1,25 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/shopspring/decimal"
 	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (a *DatabaseAdapter) GetBankAccountByAccountNumber(acct string) (BankAccountOrm, error) {
+func (a *DatabaseAdapter) GetBankAccountByAccountNumber(ctx context.Context,
+	acct string) (BankAccountOrm, error) {
 	var bankAccountOrm BankAccountOrm
 
-	if err := a.db.First(&bankAccountOrm, "account_number = ?", acct).Error; err != nil {
+	if err := a.db.WithContext(ctx).First(&bankAccountOrm, "account_number = ?", acct).Error; err != nil {
 		log.Printf("Can't find bank account number %v : %v\n", acct, err)
 		return bankAccountOrm, err
 	}
@@ -19,112 +27,445 @@ func (a *DatabaseAdapter) GetBankAccountByAccountNumber(acct string) (BankAccoun
 	return bankAccountOrm, nil
 }
 
-func (a *DatabaseAdapter) CreateExchangeRate(r BankExchangeRateOrm) (uuid.UUID, error) {
-	if err := a.db.Create(r).Error; err != nil {
+func (a *DatabaseAdapter) IsAccountNumberExist(ctx context.Context, acct string) (bool, error) {
+	var count int64
+
+	if err := a.db.WithContext(ctx).Model(&BankAccountOrm{}).Where("account_number = ?", acct).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// uniqueViolation is the SQLSTATE of an insert breaking a unique constraint, and
+// accountNumberConstraint the unique constraint on bank_accounts.account_number.
+const (
+	uniqueViolation         = "23505"
+	accountNumberConstraint = "bank_accounts_account_number_key"
+)
+
+// ErrAccountNumberExists is returned by CreateAccount when the account number was taken, e.g.
+// by a concurrent request between the number check and the insert.
+var ErrAccountNumberExists = errors.New("account number already exists")
+
+func (a *DatabaseAdapter) CreateAccount(ctx context.Context, acct BankAccountOrm,
+	initialDeposit *BankTransactionOrm) (uuid.UUID, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&acct).Error; err != nil {
+			return err
+		}
+
+		if initialDeposit != nil {
+			if err := tx.Create(initialDeposit).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == accountNumberConstraint {
+		return uuid.Nil, fmt.Errorf("%w : %v", ErrAccountNumberExists, acct.AccountNumber)
+	}
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return acct.AccountUuid, nil
+}
+
+// UpdateAccountStatus moves the account h.AccountUuid to status h.NewStatus and records h in
+// the account status history, with the status read under row lock as h.PreviousStatus. A
+// closed account must have a zero balance.
+func (a *DatabaseAdapter) UpdateAccountStatus(ctx context.Context,
+	h BankAccountStatusHistoryOrm) (BankAccountStatusHistoryOrm, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lockedAccounts, err := lockBankAccounts(tx, h.AccountUuid)
+
+		if err != nil {
+			return err
+		}
+
+		lockedAccount := lockedAccounts[h.AccountUuid]
+
+		err = dbank.CheckAccountStatusChange(lockedAccount.Status, h.NewStatus, lockedAccount.CurrentBalance)
+
+		if err != nil {
+			return err
+		}
+
+		h.PreviousStatus = lockedAccount.Status
+
+		if err := tx.Model(&BankAccountOrm{}).Where("account_uuid = ?", h.AccountUuid).Updates(
+			map[string]interface{}{
+				"status":     h.NewStatus,
+				"updated_at": h.ChangedAt,
+			},
+		).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&h).Error
+	})
+
+	if err != nil {
+		return BankAccountStatusHistoryOrm{}, err
+	}
+
+	return h, nil
+}
+
+func (a *DatabaseAdapter) GetAccountPolicy(ctx context.Context,
+	accountUuid uuid.UUID) (BankAccountPolicyOrm, bool, error) {
+	return getAccountPolicy(a.db.WithContext(ctx), accountUuid)
+}
+
+func getAccountPolicy(tx *gorm.DB, accountUuid uuid.UUID) (BankAccountPolicyOrm, bool, error) {
+	var policyOrms []BankAccountPolicyOrm
+
+	if err := tx.Where("account_uuid = ?", accountUuid).Limit(1).Find(&policyOrms).Error; err != nil {
+		return BankAccountPolicyOrm{}, false, err
+	}
+
+	if len(policyOrms) == 0 {
+		return BankAccountPolicyOrm{}, false, nil
+	}
+
+	return policyOrms[0], true, nil
+}
+
+// SaveAccountPolicy creates the policy of the account p.AccountUuid, or replaces its limits.
+func (a *DatabaseAdapter) SaveAccountPolicy(ctx context.Context,
+	p BankAccountPolicyOrm) (BankAccountPolicyOrm, error) {
+	if err := a.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"overdraft_limit", "max_transaction_amount",
+			"daily_outgoing_limit", "monthly_outgoing_limit", "updated_by", "updated_at"}),
+	}).Create(&p).Error; err != nil {
+		return BankAccountPolicyOrm{}, err
+	}
+
+	return p, nil
+}
+
+// checkOutgoing checks sending amount from the locked account acct against its policy, the
+// daily and monthly totals are summed from periods.
+//
+// The policy rules are dbank.AccountPolicy.CheckOutgoing, but they must be checked here, in the
+// transaction holding the account row lock : the balance and totals read before the lock may
+// change before the transaction is booked, and concurrent debits checked in the application
+// would each pass limits they breach together.
+func checkOutgoing(tx *gorm.DB, acct BankAccountOrm, amount decimal.Decimal,
+	periods dbank.OutgoingPeriods) error {
+	policyOrm, _, err := getAccountPolicy(tx, acct.AccountUuid)
+
+	if err != nil {
+		return err
+	}
+
+	policy := dbank.AccountPolicy{
+		AccountNumber:        acct.AccountNumber,
+		Currency:             acct.Currency,
+		OverdraftLimit:       policyOrm.OverdraftLimit,
+		MaxTransactionAmount: policyOrm.MaxTransactionAmount,
+		DailyOutgoingLimit:   policyOrm.DailyOutgoingLimit,
+		MonthlyOutgoingLimit: policyOrm.MonthlyOutgoingLimit,
+	}
+
+	var totals dbank.OutgoingTotals
+
+	if policy.HasOutgoingLimits() {
+		if totals, err = sumOutgoing(tx, acct.AccountUuid, periods); err != nil {
+			return err
+		}
+	}
+
+	return policy.CheckOutgoing(acct.AccountNumber, acct.CurrentBalance, amount, totals)
+}
+
+// sumOutgoing returns the amounts sent by the account accountUuid since the starts of periods,
+// by booking timestamp.
+func sumOutgoing(tx *gorm.DB, accountUuid uuid.UUID, periods dbank.OutgoingPeriods) (dbank.OutgoingTotals, error) {
+	var totals struct {
+		DayTotal   decimal.Decimal
+		MonthTotal decimal.Decimal
+	}
+
+	if err := tx.Model(&BankTransactionOrm{}).
+		Select("COALESCE(SUM(amount) FILTER (WHERE transaction_timestamp >= ?), 0) AS day_total, "+
+			"COALESCE(SUM(amount), 0) AS month_total", periods.DayStart).
+		Where("account_uuid = ? AND transaction_type = ? AND transaction_timestamp >= ?", accountUuid,
+			dbank.TransactionTypeOut, periods.MonthStart).
+		Scan(&totals).Error; err != nil {
+		return dbank.OutgoingTotals{}, err
+	}
+
+	return dbank.OutgoingTotals{Day: totals.DayTotal, Month: totals.MonthTotal}, nil
+}
+
+func (a *DatabaseAdapter) CreateExchangeRate(ctx context.Context, r BankExchangeRateOrm) (uuid.UUID, error) {
+	if err := a.db.WithContext(ctx).Create(r).Error; err != nil {
 		return uuid.Nil, err
 	}
 
 	return r.ExchangeRateUuid, nil
 }
 
-func (a *DatabaseAdapter) GetExchangeRateAtTimestamp(fromCur string, toCur string,
-	ts time.Time) (BankExchangeRateOrm, error) {
+func (a *DatabaseAdapter) GetExchangeRateAtTimestamp(ctx context.Context, fromCur string,
+	toCur string, ts time.Time) (BankExchangeRateOrm, error) {
 	var exchangeRateOrm BankExchangeRateOrm
 
-	err := a.db.First(&exchangeRateOrm, "from_currency = ? "+" AND to_currency = ? "+
-		" AND (? BETWEEN valid_from_timestamp and valid_to_timestamp)", fromCur, toCur, ts).Error
+	// validity windows may overlap, the most recent rate wins
+	err := a.db.WithContext(ctx).Order("valid_from_timestamp DESC").First(&exchangeRateOrm, "from_currency = ? "+
+		" AND to_currency = ? "+" AND (? BETWEEN valid_from_timestamp and valid_to_timestamp)",
+		fromCur, toCur, ts).Error
 
 	return exchangeRateOrm, err
 }
 
-func (a *DatabaseAdapter) CreateTransaction(acct BankAccountOrm, t BankTransactionOrm) (uuid.UUID, error) {
-	tx := a.db.Begin()
+// lockBankAccounts reads the given accounts with SELECT ... FOR UPDATE inside tx, so their
+// balances can't change until tx ends. Rows are locked in account_uuid order to avoid
+// deadlocks between concurrent transfers on the same pair of accounts.
+func lockBankAccounts(tx *gorm.DB, accountUuids ...uuid.UUID) (map[uuid.UUID]BankAccountOrm, error) {
+	var bankAccountOrms []BankAccountOrm
 
-	if err := tx.Create(t).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_uuid IN ?", accountUuids).
+		Order("account_uuid").
+		Find(&bankAccountOrms).Error; err != nil {
+		return nil, err
 	}
 
-	// recalculate current balance
-	newAmount := t.Amount
+	res := make(map[uuid.UUID]BankAccountOrm, len(bankAccountOrms))
+
+	for _, acct := range bankAccountOrms {
+		res[acct.AccountUuid] = acct
+	}
 
-	if t.TransactionType == dbank.TransactionTypeOut {
-		newAmount = -1 * t.Amount
+	for _, accountUuid := range accountUuids {
+		if _, ok := res[accountUuid]; !ok {
+			return nil, fmt.Errorf("bank account %v : %w", accountUuid, gorm.ErrRecordNotFound)
+		}
 	}
 
-	newAccountBalance := acct.CurrentBalance + newAmount
+	return res, nil
+}
 
-	if err := tx.Model(&acct).Updates(
+// addBalance atomically adds delta (which may be negative) to the account balance.
+func addBalance(tx *gorm.DB, accountUuid uuid.UUID, delta decimal.Decimal) error {
+	return tx.Model(&BankAccountOrm{}).Where("account_uuid = ?", accountUuid).Updates(
 		map[string]interface{}{
-			"current_balance": newAccountBalance,
+			"current_balance": gorm.Expr("current_balance + ?", delta),
 			"updated_at":      time.Now(),
 		},
-	).Error; err != nil {
-		tx.Rollback()
-		return uuid.Nil, err
+	).Error
+}
+
+// CreateTransaction stores t and updates the balance of acct. An [out] transaction is checked
+// against the account policy, with the outgoing totals summed from periods.
+func (a *DatabaseAdapter) CreateTransaction(ctx context.Context, acct BankAccountOrm,
+	t BankTransactionOrm, periods dbank.OutgoingPeriods) (uuid.UUID, error) {
+	// any other type would be booked as a credit
+	if t.TransactionType != dbank.TransactionTypeIn && t.TransactionType != dbank.TransactionTypeOut {
+		return uuid.Nil, fmt.Errorf("%w : %v", dbank.ErrInvalidTransactionType, t.TransactionType)
 	}
 
-	tx.Commit()
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lockedAccounts, err := lockBankAccounts(tx, acct.AccountUuid)
+
+		if err != nil {
+			return err
+		}
+
+		lockedAccount := lockedAccounts[acct.AccountUuid]
+
+		// account status and policy are checked against the locked row
+		if t.TransactionType == dbank.TransactionTypeOut {
+			err = dbank.CheckDebit(lockedAccount.AccountNumber, lockedAccount.Status)
+		} else {
+			err = dbank.CheckCredit(lockedAccount.AccountNumber, lockedAccount.Status)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		newAmount := t.Amount
+
+		if t.TransactionType == dbank.TransactionTypeOut {
+			if err := checkOutgoing(tx, lockedAccount, t.Amount, periods); err != nil {
+				return err
+			}
+
+			newAmount = t.Amount.Neg()
+		}
+
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+
+		return addBalance(tx, acct.AccountUuid, newAmount)
+	})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
 
 	return t.TransactionUuid, nil
 }
 
-func (a *DatabaseAdapter) CreateTransfer(transfer BankTransferOrm) (uuid.UUID, error) {
-	if err := a.db.Create(transfer).Error; err != nil {
+func (a *DatabaseAdapter) GetTransactionByIdempotencyKey(ctx context.Context,
+	key string) (BankTransactionOrm, bool, error) {
+	var transactionOrms []BankTransactionOrm
+
+	if err := a.db.WithContext(ctx).Where("idempotency_key = ?", key).Limit(1).Find(&transactionOrms).Error; err != nil {
+		return BankTransactionOrm{}, false, err
+	}
+
+	if len(transactionOrms) == 0 {
+		return BankTransactionOrm{}, false, nil
+	}
+
+	return transactionOrms[0], true, nil
+}
+
+// BankTransactionCursor is the position of a transaction in the
+// (value_timestamp, transaction_uuid) descending order used by ListTransactions.
+type BankTransactionCursor struct {
+	ValueTimestamp  time.Time
+	TransactionUuid uuid.UUID
+}
+
+// BankTransactionFilter selects the transactions of one account returned by ListTransactions,
+// the timestamps bound their value timestamp. Zero values are not filtered on.
+type BankTransactionFilter struct {
+	AccountUuid     uuid.UUID
+	FromTimestamp   time.Time // inclusive
+	ToTimestamp     time.Time // exclusive
+	TransactionType string
+	After           *BankTransactionCursor
+	Limit           int
+}
+
+// ListTransactions returns the transactions matching f, latest value timestamp first. Pages are read with
+// keyset pagination: f.After is the cursor of the last transaction on the previous page.
+func (a *DatabaseAdapter) ListTransactions(ctx context.Context, f BankTransactionFilter) ([]BankTransactionOrm, error) {
+	var transactionOrms []BankTransactionOrm
+
+	q := a.db.WithContext(ctx).Where("account_uuid = ?", f.AccountUuid)
+
+	if !f.FromTimestamp.IsZero() {
+		q = q.Where("value_timestamp >= ?", f.FromTimestamp)
+	}
+
+	if !f.ToTimestamp.IsZero() {
+		q = q.Where("value_timestamp < ?", f.ToTimestamp)
+	}
+
+	if f.TransactionType != "" {
+		q = q.Where("transaction_type = ?", f.TransactionType)
+	}
+
+	if f.After != nil {
+		q = q.Where("(value_timestamp, transaction_uuid) < (?, ?)",
+			f.After.ValueTimestamp, f.After.TransactionUuid)
+	}
+
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+
+	if err := q.Order("value_timestamp DESC, transaction_uuid DESC").
+		Find(&transactionOrms).Error; err != nil {
+		return nil, err
+	}
+
+	return transactionOrms, nil
+}
+
+func (a *DatabaseAdapter) CreateTransfer(ctx context.Context, transfer BankTransferOrm) (uuid.UUID, error) {
+	if err := a.db.WithContext(ctx).Create(transfer).Error; err != nil {
 		return uuid.Nil, err
 	}
 
 	return transfer.TransferUuid, nil
 }
 
-func (a *DatabaseAdapter) CreateTransferTransactionPair(fromAccountOrm BankAccountOrm,
-	toAccountOrm BankAccountOrm, fromTransactionOrm BankTransactionOrm,
-	toTransactionOrm BankTransactionOrm) (bool, error) {
-	tx := a.db.Begin()
+func (a *DatabaseAdapter) GetTransferByIdempotencyKey(ctx context.Context,
+	key string) (BankTransferOrm, bool, error) {
+	var transferOrms []BankTransferOrm
 
-	if err := tx.Create(fromTransactionOrm).Error; err != nil {
-		tx.Rollback()
-		return false, err
+	if err := a.db.WithContext(ctx).Where("idempotency_key = ?", key).Limit(1).Find(&transferOrms).Error; err != nil {
+		return BankTransferOrm{}, false, err
 	}
 
-	if err := tx.Create(toTransactionOrm).Error; err != nil {
-		tx.Rollback()
-		return false, err
+	if len(transferOrms) == 0 {
+		return BankTransferOrm{}, false, nil
 	}
 
-	// recalculate current balance (fromAccount)
-	fromAccountBalanceNew := fromAccountOrm.CurrentBalance - fromTransactionOrm.Amount
+	return transferOrms[0], true, nil
+}
 
-	if err := tx.Model(&fromAccountOrm).Updates(
-		map[string]interface{}{
-			"current_balance": fromAccountBalanceNew,
-			"updated_at":      time.Now(),
-		},
-	).Error; err != nil {
-		tx.Rollback()
-		return false, err
-	}
+// CreateTransferTransactionPair stores the transactions of a transfer and updates both balances.
+// The source account policy is checked with the outgoing totals summed from periods.
+func (a *DatabaseAdapter) CreateTransferTransactionPair(ctx context.Context, fromAccountOrm BankAccountOrm,
+	toAccountOrm BankAccountOrm, fromTransactionOrm BankTransactionOrm,
+	toTransactionOrm BankTransactionOrm, periods dbank.OutgoingPeriods) (bool, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lockedAccounts, err := lockBankAccounts(tx, fromAccountOrm.AccountUuid, toAccountOrm.AccountUuid)
 
-	// recalculate current balance (toAccount)
-	toAccountBalanceNew := toAccountOrm.CurrentBalance + toTransactionOrm.Amount
+		if err != nil {
+			return err
+		}
 
-	if err := tx.Model(&toAccountOrm).Updates(
-		map[string]interface{}{
-			"current_balance": toAccountBalanceNew,
-			"updated_at":      time.Now(),
-		},
-	).Error; err != nil {
-		tx.Rollback()
+		lockedFromAccount := lockedAccounts[fromAccountOrm.AccountUuid]
+		lockedToAccount := lockedAccounts[toAccountOrm.AccountUuid]
+
+		// check account status against the locked rows
+		if err := dbank.CheckDebit(lockedFromAccount.AccountNumber, lockedFromAccount.Status); err != nil {
+			return err
+		}
+
+		if err := dbank.CheckCredit(lockedToAccount.AccountNumber, lockedToAccount.Status); err != nil {
+			return err
+		}
+
+		// check policy (fromAccount) against the locked row
+		if err := checkOutgoing(tx, lockedFromAccount, fromTransactionOrm.Amount, periods); err != nil {
+			return err
+		}
+
+		if err := tx.Create(fromTransactionOrm).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(toTransactionOrm).Error; err != nil {
+			return err
+		}
+
+		// recalculate current balance (fromAccount)
+		if err := addBalance(tx, fromAccountOrm.AccountUuid, fromTransactionOrm.Amount.Neg()); err != nil {
+			return err
+		}
+
+		// recalculate current balance (toAccount)
+		return addBalance(tx, toAccountOrm.AccountUuid, toTransactionOrm.Amount)
+	})
+
+	if err != nil {
 		return false, err
 	}
 
-	tx.Commit()
-
 	return true, nil
 }
 
-func (a *DatabaseAdapter) UpdateTransferStatus(transfer BankTransferOrm, status bool) error {
-	if err := a.db.Model(&transfer).Updates(
+func (a *DatabaseAdapter) UpdateTransferStatus(ctx context.Context, transfer BankTransferOrm,
+	status bool) error {
+	if err := a.db.WithContext(ctx).Model(&transfer).Updates(
 		map[string]interface{}{
 			"transfer_success": status,
 			"updated_at":       time.Now(),
@@ -135,3 +476,22 @@ func (a *DatabaseAdapter) UpdateTransferStatus(transfer BankTransferOrm, status
 
 	return nil
 }
+
+// BankUnitOfWork is the set of bank writes that RunInTransaction can group into one
+// database transaction.
+type BankUnitOfWork interface {
+	CreateTransfer(ctx context.Context, transfer BankTransferOrm) (uuid.UUID, error)
+	CreateTransferTransactionPair(ctx context.Context, fromAccountOrm BankAccountOrm,
+		toAccountOrm BankAccountOrm, fromTransactionOrm BankTransactionOrm,
+		toTransactionOrm BankTransactionOrm, periods dbank.OutgoingPeriods) (bool, error)
+	UpdateTransferStatus(ctx context.Context, transfer BankTransferOrm, status bool) error
+}
+
+// RunInTransaction runs fn in a single database transaction. Every write done through uow is
+// committed when fn returns nil, and rolled back as a whole otherwise, or when ctx is done
+// before the commit.
+func (a *DatabaseAdapter) RunInTransaction(ctx context.Context, fn func(uow BankUnitOfWork) error) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&DatabaseAdapter{db: tx})
+	})
+}
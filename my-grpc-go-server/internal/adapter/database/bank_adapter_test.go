@@ -0,0 +1,416 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/shopspring/decimal"
+	dbmigration "github.com/timpamungkas/my-grpc-go-server/db"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+// testDsnEnv names the variable holding the DSN of a disposable Postgres database, the tests
+// needing a database are skipped without it.
+const testDsnEnv = "GRPC_SERVER_TEST_DSN"
+
+func openTestDatabase(t *testing.T) *DatabaseAdapter {
+	t.Helper()
+
+	dsn := os.Getenv(testDsnEnv)
+
+	if dsn == "" {
+		t.Skipf("%v is not set", testDsnEnv)
+	}
+
+	sqlDB, err := sql.Open("pgx", dsn)
+
+	if err != nil {
+		t.Fatalf("can't connect database : %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	if err := dbmigration.Migrate(sqlDB, "file://../../../db/migrations"); err != nil {
+		t.Fatalf("can't migrate database : %v", err)
+	}
+
+	a, err := NewDatabaseAdapter(sqlDB)
+
+	if err != nil {
+		t.Fatalf("can't create database adapter : %v", err)
+	}
+
+	return a
+}
+
+func createTestAccount(t *testing.T, a *DatabaseAdapter, balance decimal.Decimal) BankAccountOrm {
+	t.Helper()
+
+	now := time.Now()
+	acct := BankAccountOrm{
+		AccountUuid:    uuid.New(),
+		AccountNumber:  "T" + strings.ReplaceAll(uuid.NewString(), "-", "")[:19],
+		AccountName:    "Concurrency test",
+		Currency:       "USD",
+		CurrentBalance: balance,
+		Status:         dbank.AccountStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if _, err := a.CreateAccount(context.Background(), acct, nil); err != nil {
+		t.Fatalf("CreateAccount : %v", err)
+	}
+
+	return acct
+}
+
+func transferTransactions(from BankAccountOrm, to BankAccountOrm,
+	amount decimal.Decimal) (BankTransactionOrm, BankTransactionOrm) {
+	now := time.Now()
+	out := BankTransactionOrm{
+		TransactionUuid:      uuid.New(),
+		AccountUuid:          from.AccountUuid,
+		TransactionTimestamp: now,
+		ValueTimestamp:       now,
+		Amount:               amount,
+		TransactionType:      dbank.TransactionTypeOut,
+		Notes:                "Concurrency test to " + to.AccountNumber,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	in := out
+	in.TransactionUuid = uuid.New()
+	in.AccountUuid = to.AccountUuid
+	in.TransactionType = dbank.TransactionTypeIn
+	in.Notes = "Concurrency test from " + from.AccountNumber
+
+	return out, in
+}
+
+// TestOpposingTransfers runs transfers A to B and B to A in parallel. Both lock the two account
+// rows, in the same order, so none deadlocks and no balance update is lost.
+func TestOpposingTransfers(t *testing.T) {
+	a := openTestDatabase(t)
+
+	initial := decimal.NewFromInt(1000)
+	accts := []BankAccountOrm{createTestAccount(t, a, initial), createTestAccount(t, a, initial)}
+
+	const workers = 8
+	const transfers = 25
+
+	amount := decimal.NewFromInt(1)
+	periods := dbank.NewOutgoingPeriods(time.Now(), time.UTC)
+
+	// a deadlock is reported by Postgres, a lock wait never ending by the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*transfers)
+
+	for w := 0; w < workers; w++ {
+		from, to := accts[w%2], accts[(w+1)%2]
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < transfers; i++ {
+				out, in := transferTransactions(from, to, amount)
+
+				if _, err := a.CreateTransferTransactionPair(ctx, from, to, out, in, periods); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("CreateTransferTransactionPair : %v", err)
+	}
+
+	total := decimal.Zero
+
+	for _, acct := range accts {
+		got, err := a.GetBankAccountByAccountNumber(context.Background(), acct.AccountNumber)
+
+		if err != nil {
+			t.Fatalf("GetBankAccountByAccountNumber : %v", err)
+		}
+
+		// as many transfers went each way
+		if !got.CurrentBalance.Equal(initial) {
+			t.Errorf("balance of %v = %v, want %v", acct.AccountNumber, got.CurrentBalance, initial)
+		}
+
+		transactions, err := a.ListTransactions(context.Background(),
+			BankTransactionFilter{AccountUuid: acct.AccountUuid})
+
+		if err != nil {
+			t.Fatalf("ListTransactions : %v", err)
+		}
+
+		if len(transactions) != workers*transfers {
+			t.Errorf("transactions of %v = %v, want %v", acct.AccountNumber, len(transactions),
+				workers*transfers)
+		}
+
+		total = total.Add(got.CurrentBalance)
+	}
+
+	if want := initial.Mul(decimal.NewFromInt(2)); !total.Equal(want) {
+		t.Errorf("total balance = %v, want %v", total, want)
+	}
+}
+
+// createTestTransaction stores an [out] or [in] transaction of acct booked at booked, without
+// updating its balance.
+func createTestTransaction(t *testing.T, a *DatabaseAdapter, acct BankAccountOrm, ttype string,
+	amount string, booked time.Time) {
+	t.Helper()
+
+	now := time.Now()
+	transaction := BankTransactionOrm{
+		TransactionUuid:      uuid.New(),
+		AccountUuid:          acct.AccountUuid,
+		TransactionTimestamp: booked,
+		// value dated elsewhere, totals follow the booking timestamp
+		ValueTimestamp:  booked.AddDate(0, 0, 1),
+		Amount:          decimal.RequireFromString(amount),
+		TransactionType: ttype,
+		Notes:           "Policy test",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := a.db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction : %v", err)
+	}
+}
+
+func TestSumOutgoing(t *testing.T) {
+	a := openTestDatabase(t)
+	acct := createTestAccount(t, a, decimal.NewFromInt(1000))
+
+	loc, err := time.LoadLocation("America/New_York")
+
+	if err != nil {
+		t.Fatalf("LoadLocation : %v", err)
+	}
+
+	periods := dbank.NewOutgoingPeriods(time.Date(2026, 3, 15, 12, 0, 0, 0, loc), loc)
+
+	createTestTransaction(t, a, acct, dbank.TransactionTypeOut, "1", periods.MonthStart.Add(-time.Second))
+	createTestTransaction(t, a, acct, dbank.TransactionTypeOut, "10", periods.MonthStart)
+	createTestTransaction(t, a, acct, dbank.TransactionTypeOut, "20", periods.DayStart.Add(-time.Second))
+	createTestTransaction(t, a, acct, dbank.TransactionTypeOut, "100", periods.DayStart)
+	createTestTransaction(t, a, acct, dbank.TransactionTypeOut, "200", periods.DayStart.Add(time.Hour))
+	createTestTransaction(t, a, acct, dbank.TransactionTypeIn, "5000", periods.DayStart.Add(time.Hour))
+
+	totals, err := sumOutgoing(a.db, acct.AccountUuid, periods)
+
+	if err != nil {
+		t.Fatalf("sumOutgoing : %v", err)
+	}
+
+	if !totals.Day.Equal(decimal.NewFromInt(300)) || !totals.Month.Equal(decimal.NewFromInt(330)) {
+		t.Errorf("sumOutgoing = %+v, want day 300, month 330", totals)
+	}
+
+	empty := createTestAccount(t, a, decimal.Zero)
+
+	if totals, err := sumOutgoing(a.db, empty.AccountUuid, periods); err != nil ||
+		!totals.Day.IsZero() || !totals.Month.IsZero() {
+		t.Errorf("sumOutgoing without transactions = %+v, %v, want zero totals", totals, err)
+	}
+}
+
+func TestCheckOutgoing(t *testing.T) {
+	a := openTestDatabase(t)
+	periods := dbank.NewOutgoingPeriods(time.Now(), time.UTC)
+
+	limited := createTestAccount(t, a, decimal.NewFromInt(1000))
+
+	if _, err := a.SaveAccountPolicy(context.Background(), BankAccountPolicyOrm{
+		AccountUuid:          limited.AccountUuid,
+		OverdraftLimit:       decimal.NewFromInt(50),
+		DailyOutgoingLimit:   decimal.NewFromInt(500),
+		MonthlyOutgoingLimit: decimal.NewFromInt(2000),
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}); err != nil {
+		t.Fatalf("SaveAccountPolicy : %v", err)
+	}
+
+	createTestTransaction(t, a, limited, dbank.TransactionTypeOut, "400", periods.DayStart)
+
+	// no stored policy is the zero policy : no overdraft, no outgoing limits
+	unlimited := createTestAccount(t, a, decimal.NewFromInt(100))
+	createTestTransaction(t, a, unlimited, dbank.TransactionTypeOut, "99999", periods.DayStart)
+
+	overdrawn := limited
+	overdrawn.CurrentBalance = decimal.NewFromInt(-20)
+
+	tests := []struct {
+		name      string
+		acct      BankAccountOrm
+		amount    string
+		wantLimit string
+	}{
+		{"daily limit exactly", limited, "100", ""},
+		{"daily limit one cent over", limited, "100.01", dbank.LimitDailyOutgoing},
+		{"overdraft floor exactly", overdrawn, "30", ""},
+		{"overdraft floor one cent over", overdrawn, "30.01", dbank.LimitOverdraft},
+		{"zero policy whole balance", unlimited, "100", ""},
+		{"zero policy one cent over balance", unlimited, "100.01", dbank.LimitOverdraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOutgoing(a.db, tt.acct, decimal.RequireFromString(tt.amount), periods)
+
+			var limitErr *dbank.AccountLimitError
+
+			switch {
+			case tt.wantLimit == "" && err != nil:
+				t.Errorf("checkOutgoing = %v, want nil", err)
+			case tt.wantLimit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != tt.wantLimit):
+				t.Errorf("checkOutgoing = %v, want %v limit error", err, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestListTransactionsByValueDate(t *testing.T) {
+	a := openTestDatabase(t)
+	acct := createTestAccount(t, a, decimal.Zero)
+	base := time.Now().UTC().Truncate(time.Microsecond)
+
+	// booked in this order, value dated out of order, "tie a" and "tie b" share a value date
+	transactions := []struct {
+		notes  string
+		offset time.Duration
+	}{
+		{"backdated", -48 * time.Hour},
+		{"tie a", 0},
+		{"future", 12 * time.Hour},
+		{"tie b", 0},
+		{"oldest", -96 * time.Hour},
+	}
+	uuids := map[string]uuid.UUID{}
+
+	for i, tr := range transactions {
+		now := time.Now()
+		transaction := BankTransactionOrm{
+			TransactionUuid:      uuid.New(),
+			AccountUuid:          acct.AccountUuid,
+			TransactionTimestamp: base.Add(time.Duration(i) * time.Minute),
+			ValueTimestamp:       base.Add(tr.offset),
+			Amount:               decimal.NewFromInt(1),
+			TransactionType:      dbank.TransactionTypeIn,
+			Notes:                tr.notes,
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		}
+
+		if err := a.db.Create(&transaction).Error; err != nil {
+			t.Fatalf("create transaction : %v", err)
+		}
+
+		uuids[tr.notes] = transaction.TransactionUuid
+	}
+
+	// the tie is broken by the greater uuid first
+	ties := []string{"tie a", "tie b"}
+
+	if uuids["tie a"].String() < uuids["tie b"].String() {
+		ties = []string{"tie b", "tie a"}
+	}
+
+	tests := []struct {
+		name   string
+		filter BankTransactionFilter
+		want   []string
+	}{
+		{"latest value date first", BankTransactionFilter{},
+			[]string{"future", ties[0], ties[1], "backdated", "oldest"}},
+		{"limit", BankTransactionFilter{Limit: 2}, []string{"future", ties[0]}},
+		{"after a tie", BankTransactionFilter{After: &BankTransactionCursor{ValueTimestamp: base,
+			TransactionUuid: uuids[ties[0]]}}, []string{ties[1], "backdated", "oldest"}},
+		{"from is inclusive", BankTransactionFilter{FromTimestamp: base.Add(-48 * time.Hour)},
+			[]string{"future", ties[0], ties[1], "backdated"}},
+		{"to is exclusive", BankTransactionFilter{ToTimestamp: base}, []string{"backdated", "oldest"}},
+		{"one instant", BankTransactionFilter{FromTimestamp: base, ToTimestamp: base.Add(time.Microsecond)},
+			[]string{ties[0], ties[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.AccountUuid = acct.AccountUuid
+
+			res, err := a.ListTransactions(context.Background(), filter)
+
+			if err != nil {
+				t.Fatalf("ListTransactions : %v", err)
+			}
+
+			got := make([]string, 0, len(res))
+
+			for _, r := range res {
+				got = append(got, r.Notes)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ListTransactions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAccountNumberExists(t *testing.T) {
+	a := openTestDatabase(t)
+	existing := createTestAccount(t, a, decimal.Zero)
+
+	sameNumber := existing
+	sameNumber.AccountUuid = uuid.New()
+
+	// a unique violation of another constraint is not a taken account number
+	sameUuid := existing
+	sameUuid.AccountNumber = "T" + strings.ReplaceAll(uuid.NewString(), "-", "")[:19]
+
+	tests := []struct {
+		name      string
+		acct      BankAccountOrm
+		wantTaken bool
+	}{
+		{"same number", sameNumber, true},
+		{"same uuid", sameUuid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.CreateAccount(context.Background(), tt.acct, nil)
+
+			if err == nil || errors.Is(err, ErrAccountNumberExists) != tt.wantTaken {
+				t.Errorf("CreateAccount = %v, want an error, %v only when taken", err, ErrAccountNumberExists)
+			}
+		})
+	}
+}
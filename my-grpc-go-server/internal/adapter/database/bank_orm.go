@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type BankAccountOrm struct {
@@ -11,10 +12,16 @@ type BankAccountOrm struct {
 	AccountNumber  string
 	AccountName    string
 	Currency       string
-	CurrentBalance float64
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Transactions   []BankTransactionOrm `gorm:"foreignKey:AccountUuid"`
+	CurrentBalance decimal.Decimal `gorm:"type:numeric(15,2)"`
+	// OwnerSubject is the authenticated subject owning the account, nil for accounts created
+	// without authentication
+	OwnerSubject *string
+	// TimeZone is the IANA time zone defining the account business day, nil for the default
+	TimeZone     *string
+	Status       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Transactions []BankTransactionOrm `gorm:"foreignKey:AccountUuid"`
 }
 
 func (BankAccountOrm) TableName() string {
@@ -25,9 +32,11 @@ type BankTransactionOrm struct {
 	TransactionUuid      uuid.UUID `gorm:"primaryKey"`
 	AccountUuid          uuid.UUID
 	TransactionTimestamp time.Time
-	Amount               float64
+	ValueTimestamp       time.Time
+	Amount               decimal.Decimal `gorm:"type:numeric(15,2)"`
 	TransactionType      string
 	Notes                string
+	IdempotencyKey       *string
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 }
@@ -40,7 +49,7 @@ type BankExchangeRateOrm struct {
 	ExchangeRateUuid   uuid.UUID `gorm:"primaryKey"`
 	FromCurrency       string
 	ToCurrency         string
-	Rate               float64
+	Rate               decimal.Decimal `gorm:"type:numeric(20,10)"`
 	ValidFromTimestamp time.Time
 	ValidToTimestamp   time.Time
 	CreatedAt          time.Time
@@ -56,13 +65,51 @@ type BankTransferOrm struct {
 	FromAccountUuid   uuid.UUID
 	ToAccountUuid     uuid.UUID
 	Currency          string
-	Amount            float64
+	Amount            decimal.Decimal `gorm:"type:numeric(15,2)"`
 	TransferTimestamp time.Time
 	TransferSuccess   bool
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	ExchangeRate      decimal.Decimal `gorm:"type:numeric(20,10)"`
+	ExchangeRateUuid  *uuid.UUID
+	FailureReason     *string
+	// FailureKind and FailureDetail rebuild the error of a failed transfer on replay
+	FailureKind    *string
+	FailureDetail  *string `gorm:"type:jsonb"`
+	IdempotencyKey *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func (BankTransferOrm) TableName() string {
 	return "bank_transfers"
 }
+
+type BankAccountStatusHistoryOrm struct {
+	HistoryUuid    uuid.UUID `gorm:"primaryKey"`
+	AccountUuid    uuid.UUID
+	PreviousStatus string
+	NewStatus      string
+	Reason         string
+	ChangedBy      *string
+	ChangedAt      time.Time
+}
+
+func (BankAccountStatusHistoryOrm) TableName() string {
+	return "bank_account_status_history"
+}
+
+// BankAccountPolicyOrm holds the outgoing limits of an account, zero limits are unlimited except
+// OverdraftLimit.
+type BankAccountPolicyOrm struct {
+	AccountUuid          uuid.UUID       `gorm:"primaryKey"`
+	OverdraftLimit       decimal.Decimal `gorm:"type:numeric(15,2)"`
+	MaxTransactionAmount decimal.Decimal `gorm:"type:numeric(15,2)"`
+	DailyOutgoingLimit   decimal.Decimal `gorm:"type:numeric(15,2)"`
+	MonthlyOutgoingLimit decimal.Decimal `gorm:"type:numeric(15,2)"`
+	UpdatedBy            *string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (BankAccountPolicyOrm) TableName() string {
+	return "bank_account_policies"
+}
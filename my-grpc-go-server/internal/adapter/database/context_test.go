@@ -0,0 +1,230 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+// blockingDriver is a database/sql driver whose statements block until their context is done,
+// as queries waiting on a lock or a slow database would. Transactions begin at once.
+type blockingDriver struct {
+	// started receives the statements once they run
+	started chan string
+}
+
+func (d *blockingDriver) Open(name string) (driver.Conn, error) {
+	return &blockingConn{driver: d}, nil
+}
+
+type blockingConn struct {
+	driver *blockingDriver
+}
+
+func (c *blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *blockingConn) Close() error {
+	return nil
+}
+
+func (c *blockingConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *blockingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *blockingConn) Commit() error {
+	return nil
+}
+
+func (c *blockingConn) Rollback() error {
+	return nil
+}
+
+func (c *blockingConn) block(ctx context.Context, query string) error {
+	c.driver.started <- query
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (c *blockingConn) QueryContext(ctx context.Context, query string,
+	args []driver.NamedValue) (driver.Rows, error) {
+	return nil, c.block(ctx, query)
+}
+
+func (c *blockingConn) ExecContext(ctx context.Context, query string,
+	args []driver.NamedValue) (driver.Result, error) {
+	return nil, c.block(ctx, query)
+}
+
+type blockingConnector struct {
+	driver *blockingDriver
+}
+
+func (c *blockingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c *blockingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// openBlockingDatabase returns an adapter on a blockingDriver, and its connection pool.
+func openBlockingDatabase(t *testing.T) (*DatabaseAdapter, *sql.DB, chan string) {
+	t.Helper()
+
+	started := make(chan string, 10)
+	sqlDB := sql.OpenDB(&blockingConnector{driver: &blockingDriver{started: started}})
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	a, err := NewDatabaseAdapter(sqlDB)
+
+	if err != nil {
+		t.Fatalf("NewDatabaseAdapter : %v", err)
+	}
+
+	return a, sqlDB, started
+}
+
+// waitConnectionsReleased waits for every connection to be back in the pool. A transaction
+// ended by its context is rolled back, and its connection released, by database/sql in the
+// background.
+func waitConnectionsReleased(t *testing.T, sqlDB *sql.DB) {
+	t.Helper()
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	for sqlDB.Stats().InUse != 0 {
+		if time.Now().After(deadline) {
+			t.Errorf("connections in use = %v, want 0", sqlDB.Stats().InUse)
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCancelledQueries(t *testing.T) {
+	acct := BankAccountOrm{AccountUuid: uuid.New(), AccountNumber: "A1", Currency: "USD",
+		Status: dbank.AccountStatusActive}
+	to := BankAccountOrm{AccountUuid: uuid.New(), AccountNumber: "A2", Currency: "USD",
+		Status: dbank.AccountStatusActive}
+	now := time.Now()
+	transaction := BankTransactionOrm{TransactionUuid: uuid.New(), AccountUuid: acct.AccountUuid,
+		Amount: decimal.NewFromInt(10), TransactionType: dbank.TransactionTypeOut, CreatedAt: now,
+		UpdatedAt: now}
+	periods := dbank.NewOutgoingPeriods(now, time.UTC)
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, a *DatabaseAdapter) error
+	}{
+		{"GetBankAccountByAccountNumber", func(ctx context.Context, a *DatabaseAdapter) error {
+			_, err := a.GetBankAccountByAccountNumber(ctx, "A1")
+			return err
+		}},
+		{"ListTransactions", func(ctx context.Context, a *DatabaseAdapter) error {
+			_, err := a.ListTransactions(ctx, BankTransactionFilter{AccountUuid: acct.AccountUuid, Limit: 10})
+			return err
+		}},
+		{"CreateTransaction", func(ctx context.Context, a *DatabaseAdapter) error {
+			_, err := a.CreateTransaction(ctx, acct, transaction, periods)
+			return err
+		}},
+		{"CreateTransferTransactionPair", func(ctx context.Context, a *DatabaseAdapter) error {
+			_, err := a.CreateTransferTransactionPair(ctx, acct, to, transaction, transaction, periods)
+			return err
+		}},
+		{"RunInTransaction", func(ctx context.Context, a *DatabaseAdapter) error {
+			return a.RunInTransaction(ctx, func(uow BankUnitOfWork) error {
+				_, err := uow.CreateTransfer(ctx, BankTransferOrm{TransferUuid: uuid.New(), CreatedAt: now,
+					UpdatedAt: now})
+				return err
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, sqlDB, started := openBlockingDatabase(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error, 1)
+
+			go func() {
+				done <- tt.call(ctx, a)
+			}()
+
+			select {
+			case <-started:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("no query started")
+			}
+
+			cancel()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("error = %v, want %v", err, context.Canceled)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("query still running after its context was cancelled")
+			}
+
+			waitConnectionsReleased(t, sqlDB)
+		})
+	}
+}
+
+func TestExpiredQuery(t *testing.T) {
+	a, _, _ := openBlockingDatabase(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := a.GetBankAccountByAccountNumber(ctx, "A1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCreateTransactionInvalidType(t *testing.T) {
+	a, _, started := openBlockingDatabase(t)
+	acct := BankAccountOrm{AccountUuid: uuid.New(), AccountNumber: "A1", Currency: "USD",
+		Status: dbank.AccountStatusActive}
+
+	for _, ttype := range []string{"", dbank.TransactionTypeUnknown, "in"} {
+		transaction := BankTransactionOrm{TransactionUuid: uuid.New(), AccountUuid: acct.AccountUuid,
+			Amount: decimal.NewFromInt(10), TransactionType: ttype}
+
+		_, err := a.CreateTransaction(context.Background(), acct, transaction,
+			dbank.NewOutgoingPeriods(time.Now(), time.UTC))
+
+		if !errors.Is(err, dbank.ErrInvalidTransactionType) {
+			t.Errorf("CreateTransaction of type %q = %v, want %v", ttype, err, dbank.ErrInvalidTransactionType)
+		}
+	}
+
+	// refused before any query, nothing was credited
+	select {
+	case query := <-started:
+		t.Errorf("query %q ran, want none", query)
+	default:
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -21,7 +22,26 @@ func NewDatabaseAdapter(conn *sql.DB) (*DatabaseAdapter, error) {
 		return nil, fmt.Errorf("Can't connect database (gorm) : %v", err)
 	}
 
+	if err := registerMetricsCallbacks(db); err != nil {
+		return nil, fmt.Errorf("Can't register database metrics : %v", err)
+	}
+
+	if err := registerTracingCallbacks(db); err != nil {
+		return nil, fmt.Errorf("Can't register database tracing : %v", err)
+	}
+
 	return &DatabaseAdapter{
 		db: db,
 	}, nil
 }
+
+// Ping checks the database connection is alive.
+func (a *DatabaseAdapter) Ping(ctx context.Context) error {
+	sqlDB, err := a.db.DB()
+
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
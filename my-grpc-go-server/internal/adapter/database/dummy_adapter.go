@@ -1,13 +1,14 @@
 package database
 
 import (
+	"context"
 	"log"
 
 	"github.com/google/uuid"
 )
 
-func (a *DatabaseAdapter) Save(data *DummyOrm) (uuid.UUID, error) {
-	if err := a.db.Create(data).Error; err != nil {
+func (a *DatabaseAdapter) Save(ctx context.Context, data *DummyOrm) (uuid.UUID, error) {
+	if err := a.db.WithContext(ctx).Create(data).Error; err != nil {
 		log.Println("Can't create data :", err)
 		return uuid.Nil, err
 	}
@@ -15,9 +16,9 @@ func (a *DatabaseAdapter) Save(data *DummyOrm) (uuid.UUID, error) {
 	return data.UserId, nil
 }
 
-func (a *DatabaseAdapter) GetByUuid(uuid *uuid.UUID) (DummyOrm, error) {
+func (a *DatabaseAdapter) GetByUuid(ctx context.Context, uuid *uuid.UUID) (DummyOrm, error) {
 	var res DummyOrm
-	if err := a.db.First(&res, "user_id = ?", uuid).Error; err != nil {
+	if err := a.db.WithContext(ctx).First(&res, "user_id = ?", uuid).Error; err != nil {
 		log.Println("Can't get data :", err)
 		return res, err
 	}
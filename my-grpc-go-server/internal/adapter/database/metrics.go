@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// registerMetricsCallbacks times every gorm operation and counts the failed ones.
+func registerMetricsCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+
+	for _, err := range []error{
+		cb.Create().Before("*").Register("metrics:before_create", startQueryTimer),
+		cb.Create().After("*").Register("metrics:after_create", observeQuery("create")),
+		cb.Query().Before("*").Register("metrics:before_query", startQueryTimer),
+		cb.Query().After("*").Register("metrics:after_query", observeQuery("query")),
+		cb.Update().Before("*").Register("metrics:before_update", startQueryTimer),
+		cb.Update().After("*").Register("metrics:after_update", observeQuery("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", startQueryTimer),
+		cb.Delete().After("*").Register("metrics:after_delete", observeQuery("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", startQueryTimer),
+		cb.Row().After("*").Register("metrics:after_row", observeQuery("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", startQueryTimer),
+		cb.Raw().After("*").Register("metrics:after_raw", observeQuery("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func startQueryTimer(tx *gorm.DB) {
+	tx.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		start, ok := tx.InstanceGet(metricsStartKey)
+
+		if !ok {
+			return
+		}
+
+		table := tx.Statement.Table
+
+		if table == "" {
+			table = "unknown"
+		}
+
+		metrics.DbQuerySeconds.WithLabelValues(operation, table).Observe(time.Since(start.(time.Time)).Seconds())
+
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			metrics.DbQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+)
+
+// sampleCount returns the number of observations of histogram.
+func sampleCount(t *testing.T, histogram prometheus.Observer) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+
+	if err := histogram.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write : %v", err)
+	}
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestQueryMetrics(t *testing.T) {
+	a, _, started := openBlockingDatabase(t)
+	seconds := metrics.DbQuerySeconds.WithLabelValues("query", "bank_accounts")
+	errs := metrics.DbQueryErrors.WithLabelValues("query", "bank_accounts")
+	observedBefore, errsBefore := sampleCount(t, seconds), testutil.ToFloat64(errs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := a.GetBankAccountByAccountNumber(ctx, "A1"); err == nil {
+		t.Fatalf("GetBankAccountByAccountNumber succeeded, want the timeout")
+	}
+
+	<-started
+
+	if got := sampleCount(t, seconds) - observedBefore; got != 1 {
+		t.Errorf("db_query_duration_seconds observed %v queries, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(errs) - errsBefore; got != 1 {
+		t.Errorf("db_query_errors_total changed by %v, want 1", got)
+	}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingSpanKey = "tracing:span"
+
+var tracer = otel.Tracer("github.com/timpamungkas/my-grpc-go-server/internal/adapter/database")
+
+// registerTracingCallbacks wraps every gorm operation in a client span. Spans are only started
+// under a span of the statement context, so queries made outside of a traced request, like the
+// exchange rate updates, don't start traces of their own.
+func registerTracingCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+
+	for _, err := range []error{
+		cb.Create().Before("*").Register("tracing:before_create", startQuerySpan("create")),
+		cb.Create().After("*").Register("tracing:after_create", endQuerySpan),
+		cb.Query().Before("*").Register("tracing:before_query", startQuerySpan("query")),
+		cb.Query().After("*").Register("tracing:after_query", endQuerySpan),
+		cb.Update().Before("*").Register("tracing:before_update", startQuerySpan("update")),
+		cb.Update().After("*").Register("tracing:after_update", endQuerySpan),
+		cb.Delete().Before("*").Register("tracing:before_delete", startQuerySpan("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", endQuerySpan),
+		cb.Row().Before("*").Register("tracing:before_row", startQuerySpan("row")),
+		cb.Row().After("*").Register("tracing:after_row", endQuerySpan),
+		cb.Raw().Before("*").Register("tracing:before_raw", startQuerySpan("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", endQuerySpan),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func startQuerySpan(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+
+		_, span := tracer.Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(operation)),
+		)
+
+		tx.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+func endQuerySpan(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(tracingSpanKey)
+
+	if !ok {
+		return
+	}
+
+	span := v.(trace.Span)
+
+	span.SetAttributes(
+		semconv.DBSQLTable(tx.Statement.Table),
+		semconv.DBStatement(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+
+	span.End()
+}
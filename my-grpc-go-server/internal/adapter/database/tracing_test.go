@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	recorderOnce sync.Once
+	recorder     *tracetest.SpanRecorder
+)
+
+// spanRecorder returns the recorder of every span. It is installed once as the global tracer
+// provider, since the package tracer keeps delegating to the first one.
+func spanRecorder() *tracetest.SpanRecorder {
+	recorderOnce.Do(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	return recorder
+}
+
+func TestQuerySpans(t *testing.T) {
+	r := spanRecorder()
+	a, _, started := openBlockingDatabase(t)
+
+	tests := []struct {
+		name string
+		// traced starts the span of the RPC making the query
+		traced bool
+	}{
+		{"traced request", true},
+		{"untraced request", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			var parent trace.Span
+
+			if tt.traced {
+				ctx, parent = otel.Tracer("test").Start(ctx, "bank.BankService/GetCurrentBalance")
+				defer parent.End()
+			}
+
+			ended := len(r.Ended())
+
+			if _, err := a.GetBankAccountByAccountNumber(ctx, "A1"); err == nil {
+				t.Fatalf("GetBankAccountByAccountNumber succeeded, want the timeout")
+			}
+
+			<-started
+			spans := r.Ended()[ended:]
+
+			if !tt.traced {
+				if len(spans) != 0 {
+					t.Errorf("%v spans ended, want none outside of a traced request", len(spans))
+				}
+
+				return
+			}
+
+			if len(spans) != 1 {
+				t.Fatalf("%v spans ended, want the query span", len(spans))
+			}
+
+			span := spans[0]
+
+			if span.Name() != "gorm.query" || span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("span is a %v %v, want a client gorm.query", span.SpanKind(), span.Name())
+			}
+
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() ||
+				span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+				t.Errorf("span is a child of %v, want the request span %v", span.Parent().SpanID(),
+					parent.SpanContext().SpanID())
+			}
+
+			if span.Status().Code != codes.Error {
+				t.Errorf("span status = %v, want %v for the failed query", span.Status().Code, codes.Error)
+			}
+
+			for _, attr := range span.Attributes() {
+				if attr.Key == "db.sql.table" && attr.Value.AsString() != "bank_accounts" {
+					t.Errorf("db.sql.table = %v, want bank_accounts", attr.Value.AsString())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package exchangerate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shopspring/decimal"
+)
+
+// exchangeRateJson is one rate of the JSON documents read by FileProvider and HttpProvider,
+// e.g. [{"from_currency": "USD", "to_currency": "IDR", "rate": "15000.25"}]. The rate may
+// also be a JSON number.
+type exchangeRateJson struct {
+	FromCurrency string          `json:"from_currency"`
+	ToCurrency   string          `json:"to_currency"`
+	Rate         decimal.Decimal `json:"rate"`
+}
+
+type rateKey struct {
+	fromCurrency string
+	toCurrency   string
+}
+
+type rateTable map[rateKey]decimal.Decimal
+
+func (t rateTable) find(fromCur string, toCur string) (decimal.Decimal, error) {
+	rate, found := t[rateKey{fromCurrency: fromCur, toCurrency: toCur}]
+
+	if !found {
+		return decimal.Zero, fmt.Errorf("no exchange rate from %v to %v", fromCur, toCur)
+	}
+
+	if !rate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("exchange rate from %v to %v must be positive, got %v",
+			fromCur, toCur, rate)
+	}
+
+	return rate, nil
+}
+
+func decodeJsonRates(r io.Reader) (rateTable, error) {
+	var rates []exchangeRateJson
+
+	if err := json.NewDecoder(r).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("can't decode exchange rates : %v", err)
+	}
+
+	res := make(rateTable, len(rates))
+
+	for _, rate := range rates {
+		res[rateKey{fromCurrency: rate.FromCurrency, toCurrency: rate.ToCurrency}] = rate.Rate
+	}
+
+	return res, nil
+}
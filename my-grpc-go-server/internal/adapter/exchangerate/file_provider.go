@@ -0,0 +1,100 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// FileProvider reads exchange rates from a JSON or CSV file, chosen by the file extension.
+// The file is read again on every fetch, so rates can be changed while the server runs.
+//
+// A CSV file starts with the header row from_currency,to_currency,rate.
+type FileProvider struct {
+	path string
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".csv":
+	default:
+		return nil, fmt.Errorf("exchange rate file %v must be .json or .csv", path)
+	}
+
+	return &FileProvider{
+		path: path,
+	}, nil
+}
+
+func (p *FileProvider) FetchExchangeRate(ctx context.Context, fromCur string, toCur string) (decimal.Decimal, error) {
+	f, err := os.Open(p.path)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	defer f.Close()
+
+	var rates rateTable
+
+	if strings.ToLower(filepath.Ext(p.path)) == ".csv" {
+		rates, err = decodeCsvRates(f)
+	} else {
+		rates, err = decodeJsonRates(f)
+	}
+
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%v : %v", p.path, err)
+	}
+
+	return rates.find(fromCur, toCur)
+}
+
+func decodeCsvRates(r io.Reader) (rateTable, error) {
+	records, err := csv.NewReader(r).ReadAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("can't read exchange rates : %v", err)
+	}
+
+	if len(records) == 0 {
+		return rateTable{}, nil
+	}
+
+	columns := map[string]int{}
+
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{"from_currency", "to_currency", "rate"} {
+		if _, found := columns[name]; !found {
+			return nil, fmt.Errorf("missing column %v in exchange rates header", name)
+		}
+	}
+
+	res := make(rateTable, len(records)-1)
+
+	for i, record := range records[1:] {
+		rate, err := decimal.NewFromString(strings.TrimSpace(record[columns["rate"]]))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate on line %d : %v", i+2, err)
+		}
+
+		key := rateKey{
+			fromCurrency: strings.TrimSpace(record[columns["from_currency"]]),
+			toCurrency:   strings.TrimSpace(record[columns["to_currency"]]),
+		}
+
+		res[key] = rate
+	}
+
+	return res, nil
+}
@@ -0,0 +1,155 @@
+package exchangerate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// writeRates writes content to the file name under dir.
+func writeRates(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile : %v", err)
+	}
+
+	return path
+}
+
+func TestNewFileProvider(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"rates.json", false},
+		{"rates.csv", false},
+		{"RATES.CSV", false},
+		{"rates.txt", true},
+		{"rates", true},
+		{"rates.csv.bak", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if _, err := NewFileProvider(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("NewFileProvider = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileProviderFetchExchangeRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		from    string
+		to      string
+		// want is the rate, empty when the fetch fails with an error containing wantErr
+		want    string
+		wantErr string
+	}{
+		{"json", "rates.json", `[{"from_currency": "USD", "to_currency": "IDR", "rate": "15000.25"}]`,
+			"USD", "IDR", "15000.25", ""},
+		{"csv", "rates.csv", "from_currency,to_currency,rate\nUSD,IDR,15000.25\n", "USD", "IDR", "15000.25", ""},
+		{"csv columns by header", "rates.csv", "rate,to_currency,from_currency\n15000.25,IDR,USD\n",
+			"USD", "IDR", "15000.25", ""},
+		{"csv extra column", "rates.csv", "from_currency,source,to_currency,rate\nUSD,bank,IDR,15000.25\n",
+			"USD", "IDR", "15000.25", ""},
+		{"csv spaces trimmed", "rates.csv", " from_currency , to_currency , rate \n USD , IDR , 15000.25 \n",
+			"USD", "IDR", "15000.25", ""},
+		{"csv missing column", "rates.csv", "from_currency,to_currency\nUSD,IDR\n", "USD", "IDR", "",
+			"missing column rate"},
+		{"csv invalid rate", "rates.csv", "from_currency,to_currency,rate\nUSD,EUR,0.92\nUSD,IDR,x\n",
+			"USD", "IDR", "", "invalid rate on line 3"},
+		{"csv header only", "rates.csv", "from_currency,to_currency,rate\n", "USD", "IDR", "",
+			"no exchange rate from USD to IDR"},
+		{"csv empty", "rates.csv", "", "USD", "IDR", "", "no exchange rate from USD to IDR"},
+		{"csv ragged", "rates.csv", "from_currency,to_currency,rate\nUSD,IDR\n", "USD", "IDR", "",
+			"can't read exchange rates"},
+		{"unknown pair", "rates.csv", "from_currency,to_currency,rate\nUSD,IDR,15000\n", "IDR", "USD", "",
+			"no exchange rate from IDR to USD"},
+		{"negative rate", "rates.csv", "from_currency,to_currency,rate\nUSD,IDR,-1\n", "USD", "IDR", "",
+			"must be positive"},
+		{"malformed json", "rates.json", `[{"from_currency": "USD",`, "USD", "IDR", "",
+			"can't decode exchange rates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeRates(t, t.TempDir(), tt.file, tt.content)
+			p, err := NewFileProvider(path)
+
+			if err != nil {
+				t.Fatalf("NewFileProvider : %v", err)
+			}
+
+			rate, err := p.FetchExchangeRate(context.Background(), tt.from, tt.to)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FetchExchangeRate = %v, %v, want an error containing %q", rate, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || !rate.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("FetchExchangeRate = %v, %v, want %v", rate, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileProviderRereadsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRates(t, dir, "rates.csv", "from_currency,to_currency,rate\nUSD,IDR,15000\n")
+	p, err := NewFileProvider(path)
+
+	if err != nil {
+		t.Fatalf("NewFileProvider : %v", err)
+	}
+
+	for _, step := range []struct {
+		content string
+		want    string
+	}{
+		{"", "15000"},
+		{"from_currency,to_currency,rate\nUSD,IDR,15100\n", "15100"},
+		{"from_currency,to_currency,rate\nUSD,IDR,x\n", ""},
+		{"from_currency,to_currency,rate\nUSD,IDR,15200\n", "15200"},
+	} {
+		if step.content != "" {
+			writeRates(t, dir, "rates.csv", step.content)
+		}
+
+		rate, err := p.FetchExchangeRate(context.Background(), "USD", "IDR")
+
+		if step.want == "" {
+			if err == nil {
+				t.Errorf("FetchExchangeRate = %v, want an error on the invalid file", rate)
+			}
+
+			continue
+		}
+
+		if err != nil || !rate.Equal(decimal.RequireFromString(step.want)) {
+			t.Errorf("FetchExchangeRate = %v, %v, want %v", rate, err, step.want)
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove : %v", err)
+	}
+
+	if _, err := p.FetchExchangeRate(context.Background(), "USD", "IDR"); !os.IsNotExist(err) {
+		t.Errorf("FetchExchangeRate of a removed file = %v, want not exist", err)
+	}
+}
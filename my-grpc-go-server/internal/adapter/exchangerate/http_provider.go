@@ -0,0 +1,56 @@
+package exchangerate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HttpProvider polls an HTTP endpoint answering GET with the JSON list of rates, in the same
+// format as the JSON file of FileProvider.
+type HttpProvider struct {
+	url    string
+	client *http.Client
+}
+
+func NewHttpProvider(url string, timeout time.Duration) *HttpProvider {
+	return &HttpProvider{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (p *HttpProvider) FetchExchangeRate(ctx context.Context, fromCur string, toCur string) (decimal.Decimal, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return decimal.Zero, fmt.Errorf("exchange rate endpoint %v returned %v", p.url, res.Status)
+	}
+
+	rates, err := decodeJsonRates(res.Body)
+
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%v : %v", p.url, err)
+	}
+
+	return rates.find(fromCur, toCur)
+}
@@ -0,0 +1,131 @@
+package exchangerate
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// serveRates starts a server answering every request with status and body until the test ends.
+func serveRates(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.Header.Get("Accept") != "application/json" {
+			http.Error(w, "want a GET accepting application/json", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHttpProviderFetchExchangeRate(t *testing.T) {
+	rates := `[{"from_currency": "USD", "to_currency": "IDR", "rate": "15000.25"},
+		{"from_currency": "IDR", "to_currency": "USD", "rate": 0.0000666655},
+		{"from_currency": "USD", "to_currency": "GBP", "rate": "0"}]`
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		from   string
+		to     string
+		// want is the rate, empty when the fetch fails with an error containing wantErr
+		want    string
+		wantErr string
+	}{
+		{"string rate", http.StatusOK, rates, "USD", "IDR", "15000.25", ""},
+		{"number rate", http.StatusOK, rates, "IDR", "USD", "0.0000666655", ""},
+		{"server error", http.StatusInternalServerError, rates, "USD", "IDR", "", "500 Internal Server Error"},
+		{"not found", http.StatusNotFound, "", "USD", "IDR", "", "404 Not Found"},
+		{"created", http.StatusCreated, rates, "USD", "IDR", "", "201 Created"},
+		{"malformed json", http.StatusOK, `[{"from_currency": "USD",`, "USD", "IDR", "",
+			"can't decode exchange rates"},
+		{"not a list", http.StatusOK, `{"rate": "1"}`, "USD", "IDR", "", "can't decode exchange rates"},
+		{"malformed rate", http.StatusOK, `[{"from_currency": "USD", "to_currency": "IDR", "rate": "x"}]`,
+			"USD", "IDR", "", "can't decode exchange rates"},
+		{"unknown pair", http.StatusOK, rates, "EUR", "IDR", "", "no exchange rate from EUR to IDR"},
+		{"empty list", http.StatusOK, `[]`, "USD", "IDR", "", "no exchange rate from USD to IDR"},
+		{"zero rate", http.StatusOK, rates, "USD", "GBP", "", "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serveRates(t, tt.status, tt.body)
+			p := NewHttpProvider(server.URL, time.Second)
+
+			rate, err := p.FetchExchangeRate(context.Background(), tt.from, tt.to)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FetchExchangeRate = %v, %v, want an error containing %q", rate, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || !rate.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("FetchExchangeRate = %v, %v, want %v", rate, err, tt.want)
+			}
+		})
+	}
+}
+
+// serveSlowly starts a server answering only once the request is given up by the client.
+func serveSlowly(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHttpProviderContextTimeout(t *testing.T) {
+	server := serveSlowly(t)
+	p := NewHttpProvider(server.URL, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.FetchExchangeRate(ctx, "USD", "IDR")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchExchangeRate = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("FetchExchangeRate took %v, want it to stop with its context", elapsed)
+	}
+}
+
+func TestHttpProviderClientTimeout(t *testing.T) {
+	server := serveSlowly(t)
+	p := NewHttpProvider(server.URL, 50*time.Millisecond)
+
+	_, err := p.FetchExchangeRate(context.Background(), "USD", "IDR")
+
+	var netErr net.Error
+
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("FetchExchangeRate = %v, want a timeout", err)
+	}
+}
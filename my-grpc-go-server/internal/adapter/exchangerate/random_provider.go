@@ -0,0 +1,26 @@
+package exchangerate
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/shopspring/decimal"
+)
+
+// RandomProvider makes up a whole number rate between min and max (inclusive) for any
+// currency pair. It is meant for demos only.
+type RandomProvider struct {
+	min int64
+	max int64
+}
+
+func NewRandomProvider(min int64, max int64) *RandomProvider {
+	return &RandomProvider{
+		min: min,
+		max: max,
+	}
+}
+
+func (p *RandomProvider) FetchExchangeRate(ctx context.Context, fromCur string, toCur string) (decimal.Decimal, error) {
+	return decimal.NewFromInt(p.min + rand.Int63n(p.max-p.min+1)), nil
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetAuthenticator sets the authenticator of BankService callers, required when grpc.auth is
+// enabled. Call it before Run.
+func (a *GrpcAdapter) SetAuthenticator(authn port.AuthenticatorPort) {
+	a.authenticator = authn
+}
+
+// authorizeAccount checks the caller of the RPC handled with ctx may access the account acct.
+// Without authentication, every account is accessible.
+func (a *GrpcAdapter) authorizeAccount(ctx context.Context, acct string) error {
+	if !a.cfg.Auth.Enabled {
+		return nil
+	}
+
+	p, ok := dauth.FromContext(ctx)
+
+	if !ok {
+		s := status.New(codes.Unauthenticated, dauth.ErrUnauthenticated.Error())
+		s, _ = s.WithDetails(&errdetails.ErrorInfo{
+			Domain: "my-bank-website.com",
+			Reason: "CREDENTIALS_MISSING",
+		})
+
+		return s.Err()
+	}
+
+	err := a.bankService.AuthorizeAccount(ctx, p, acct)
+
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, dauth.ErrPermissionDenied) {
+		return status.Errorf(codes.Internal, "can't authorize account %v : %v", acct, err)
+	}
+
+	s := status.New(codes.PermissionDenied, err.Error())
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: "my-bank-website.com",
+		Reason: "ACCOUNT_ACCESS_DENIED",
+		Metadata: map[string]string{
+			"account_number": acct,
+			"subject":        p.Subject,
+		},
+	})
+
+	return s.Err()
+}
+
+// authorizeAdmin checks the caller of the RPC handled with ctx is an admin. Without
+// authentication, every caller is allowed.
+func (a *GrpcAdapter) authorizeAdmin(ctx context.Context) error {
+	if !a.cfg.Auth.Enabled {
+		return nil
+	}
+
+	p, ok := dauth.FromContext(ctx)
+
+	if !ok {
+		s := status.New(codes.Unauthenticated, dauth.ErrUnauthenticated.Error())
+		s, _ = s.WithDetails(&errdetails.ErrorInfo{
+			Domain: "my-bank-website.com",
+			Reason: "CREDENTIALS_MISSING",
+		})
+
+		return s.Err()
+	}
+
+	if p.Admin {
+		return nil
+	}
+
+	s := status.New(codes.PermissionDenied, dauth.ErrPermissionDenied.Error())
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: "my-bank-website.com",
+		Reason: "ADMIN_REQUIRED",
+		Metadata: map[string]string{
+			"subject": p.Subject,
+		},
+	})
+
+	return s.Err()
+}
+
+// accountOwner returns the subject owning the accounts created by the caller of the RPC handled
+// with ctx, empty without authentication.
+func accountOwner(ctx context.Context) string {
+	p, _ := dauth.FromContext(ctx)
+
+	return p.Subject
+}
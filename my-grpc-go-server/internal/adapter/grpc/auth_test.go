@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminPorts records the admin port calls reaching the bank service.
+type adminPorts struct {
+	port.BankServicePort
+
+	calls []string
+}
+
+func (f *adminPorts) ChangeAccountStatus(ctx context.Context,
+	c dbank.AccountStatusChange) (dbank.AccountStatusChangeResult, error) {
+	f.calls = append(f.calls, "ChangeAccountStatus")
+
+	return dbank.AccountStatusChangeResult{PreviousStatus: dbank.AccountStatusActive, Status: c.Status,
+		ChangedAt: time.Now()}, nil
+}
+
+func (f *adminPorts) UpdateAccountPolicy(ctx context.Context, p dbank.AccountPolicy) (dbank.AccountPolicy, error) {
+	f.calls = append(f.calls, "UpdateAccountPolicy")
+
+	return p, nil
+}
+
+// errorReason returns the ErrorInfo reason of the status of err, empty without one.
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+
+	return ""
+}
+
+func authContext(p *dauth.Principal) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+
+	return dauth.NewContext(context.Background(), *p)
+}
+
+var adminTests = []struct {
+	name        string
+	authEnabled bool
+	principal   *dauth.Principal
+	wantCode    codes.Code
+	wantReason  string
+}{
+	{"auth disabled", false, nil, codes.OK, ""},
+	{"no principal", true, nil, codes.Unauthenticated, "CREDENTIALS_MISSING"},
+	{"token user", true, &dauth.Principal{Subject: "alice", Method: dauth.MethodJwt}, codes.PermissionDenied,
+		"ADMIN_REQUIRED"},
+	{"certificate user", true, &dauth.Principal{Subject: "x509:alice", Method: dauth.MethodMtls},
+		codes.PermissionDenied, "ADMIN_REQUIRED"},
+	{"token admin", true, &dauth.Principal{Subject: "admin", Method: dauth.MethodJwt, Admin: true}, codes.OK, ""},
+	{"certificate admin", true, &dauth.Principal{Subject: "x509:spiffe://bank/admin", Method: dauth.MethodMtls,
+		Admin: true}, codes.OK, ""},
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	for _, tt := range adminTests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewGrpcAdapter(nil, &adminPorts{}, nil,
+				config.GrpcConfig{Auth: config.AuthConfig{Enabled: tt.authEnabled}})
+
+			err := a.authorizeAdmin(authContext(tt.principal))
+
+			if status.Code(err) != tt.wantCode || errorReason(err) != tt.wantReason {
+				t.Errorf("authorizeAdmin = %v (reason %q), want %v (reason %q)", err, errorReason(err),
+					tt.wantCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAdminOnlyRpcs(t *testing.T) {
+	rpcs := []struct {
+		name string
+		call func(ctx context.Context, a *GrpcAdapter) error
+	}{
+		{"UpdateAccountStatus", func(ctx context.Context, a *GrpcAdapter) error {
+			_, err := a.UpdateAccountStatus(ctx, &bank.UpdateAccountStatusRequest{AccountNumber: "A1",
+				Status: bank.AccountStatus_ACCOUNT_STATUS_FROZEN, Reason: "test"})
+			return err
+		}},
+		{"UpdateAccountPolicy", func(ctx context.Context, a *GrpcAdapter) error {
+			_, err := a.UpdateAccountPolicy(ctx, &bank.UpdateAccountPolicyRequest{AccountNumber: "A1",
+				Policy: &bank.AccountPolicy{}})
+			return err
+		}},
+	}
+
+	for _, rpc := range rpcs {
+		for _, tt := range adminTests {
+			t.Run(rpc.name+" "+tt.name, func(t *testing.T) {
+				ports := &adminPorts{}
+				a := NewGrpcAdapter(nil, ports, nil,
+					config.GrpcConfig{Auth: config.AuthConfig{Enabled: tt.authEnabled}})
+
+				err := rpc.call(authContext(tt.principal), a)
+
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("%v = %v, want %v", rpc.name, err, tt.wantCode)
+				}
+
+				// a refused call never reaches the bank service
+				if called := len(ports.calls) > 0; called != (tt.wantCode == codes.OK) {
+					t.Errorf("bank service calls = %v, want a call only when allowed", ports.calls)
+				}
+			})
+		}
+	}
+}
@@ -9,21 +9,48 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
-	"google.golang.org/genproto/googleapis/type/date"
-	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/genproto/googleapis/type/money"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
 )
 
+const idempotencyKeyMetadata = "idempotency-key"
+
 func (a *GrpcAdapter) GetCurrentBalance(ctx context.Context,
 	req *bank.CurrentBalanceRequest) (*bank.CurrentBalanceResponse, error) {
+	if err := a.authorizeAccount(ctx, req.AccountNumber); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
-	bal, err := a.bankService.FindCurrentBalance(req.AccountNumber)
+	portCtx, span := startPortSpan(ctx, "BankService.FindCurrentBalance")
+	bal, cur, err := a.bankService.FindCurrentBalance(portCtx, req.AccountNumber)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"account %v not found", req.AccountNumber,
+		)
+	}
+
+	loc, err := a.accountLocation(ctx, req.AccountNumber)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
 
 	if err != nil {
 		return nil, status.Errorf(
@@ -33,155 +60,667 @@ func (a *GrpcAdapter) GetCurrentBalance(ctx context.Context,
 	}
 
 	return &bank.CurrentBalanceResponse{
-		Amount: bal,
-		CurrentDate: &date.Date{
-			Year:  int32(now.Year()),
-			Month: int32(now.Month()),
-			Day:   int32(now.Day()),
-		},
+		Amount:      toMoney(bal, cur),
+		CurrentDate: toDate(now.In(loc)),
 	}, nil
 }
 
+func (a *GrpcAdapter) CreateAccount(ctx context.Context,
+	req *bank.CreateAccountRequest) (*bank.CreateAccountResponse, error) {
+	initialDeposit, err := toDecimal(req.InitialDepositAmount)
+
+	if err != nil {
+		return nil, buildInvalidAmountStatusGrpc("initial_deposit_amount", err)
+	}
+
+	if cur := req.InitialDepositAmount.GetCurrencyCode(); cur != "" && cur != req.Currency {
+		return nil, buildInvalidAmountStatusGrpc("initial_deposit_amount",
+			fmt.Errorf("%w : deposit in %v, account in %v", dbank.ErrCurrencyMismatch, cur, req.Currency))
+	}
+
+	acct := dbank.Account{
+		AccountName:          req.AccountName,
+		Currency:             req.Currency,
+		InitialDepositAmount: initialDeposit,
+		Owner:                accountOwner(ctx),
+		TimeZone:             req.TimeZone,
+	}
+
+	portCtx, span := startPortSpan(ctx, "BankService.CreateAccount")
+	accountUuid, accountNumber, err := a.bankService.CreateAccount(portCtx, acct)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildCreateAccountErrorStatusGrpc(err, req)
+	}
+
+	return &bank.CreateAccountResponse{
+		AccountUuid:   accountUuid.String(),
+		AccountNumber: accountNumber,
+	}, nil
+}
+
+func buildCreateAccountErrorStatusGrpc(err error, req *bank.CreateAccountRequest) error {
+	var violation *errdetails.BadRequest_FieldViolation
+
+	switch {
+	case errors.Is(err, dbank.ErrInvalidAccountName):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "account_name",
+			Description: "Account name is required",
+		}
+	case errors.Is(err, dbank.ErrInvalidCurrency):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "currency",
+			Description: fmt.Sprintf("Currency %v is not a valid ISO 4217 code", req.Currency),
+		}
+	case errors.Is(err, dbank.ErrInvalidTimeZone):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "time_zone",
+			Description: fmt.Sprintf("Time zone %q is not an IANA time zone name", req.TimeZone),
+		}
+	case errors.Is(err, dbank.ErrInvalidInitialDeposit):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field: "initial_deposit_amount",
+			Description: fmt.Sprintf("Initial deposit %v can't be negative",
+				req.InitialDepositAmount.GetUnits()),
+		}
+	case errors.Is(err, dbank.ErrAccountNumberGeneration), errors.Is(err, dbank.ErrCreateAccountFailed):
+		s := status.New(codes.Internal, err.Error())
+		s, _ = s.WithDetails(&errdetails.Help{
+			Links: []*errdetails.Help_Link{
+				{
+					Url:         "my-bank-website.com/faq",
+					Description: "Bank FAQ",
+				},
+			},
+		})
+
+		return s.Err()
+	default:
+		return status.New(codes.Unknown, err.Error()).Err()
+	}
+
+	s := status.New(codes.InvalidArgument, err.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{violation},
+	})
+
+	return s.Err()
+}
+
+func (a *GrpcAdapter) UpdateAccountStatus(ctx context.Context,
+	req *bank.UpdateAccountStatusRequest) (*bank.UpdateAccountStatusResponse, error) {
+	if err := a.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	change := dbank.AccountStatusChange{
+		AccountNumber: req.AccountNumber,
+		Status:        fromAccountStatus(req.Status),
+		Reason:        req.Reason,
+		ChangedBy:     accountOwner(ctx),
+	}
+
+	portCtx, span := startPortSpan(ctx, "BankService.ChangeAccountStatus")
+	res, err := a.bankService.ChangeAccountStatus(portCtx, change)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildUpdateAccountStatusErrorStatusGrpc(err, req)
+	}
+
+	return &bank.UpdateAccountStatusResponse{
+		AccountNumber:  req.AccountNumber,
+		PreviousStatus: toAccountStatus(res.PreviousStatus),
+		Status:         toAccountStatus(res.Status),
+		ChangedAt:      toDatetime(res.ChangedAt.UTC()),
+	}, nil
+}
+
+// fromAccountStatus returns the domain account status of s, empty for an unspecified status.
+func fromAccountStatus(s bank.AccountStatus) string {
+	switch s {
+	case bank.AccountStatus_ACCOUNT_STATUS_ACTIVE:
+		return dbank.AccountStatusActive
+	case bank.AccountStatus_ACCOUNT_STATUS_FROZEN:
+		return dbank.AccountStatusFrozen
+	case bank.AccountStatus_ACCOUNT_STATUS_CLOSED:
+		return dbank.AccountStatusClosed
+	default:
+		return ""
+	}
+}
+
+// toAccountStatus returns the protobuf account status of the domain account status s.
+func toAccountStatus(s string) bank.AccountStatus {
+	switch s {
+	case dbank.AccountStatusActive:
+		return bank.AccountStatus_ACCOUNT_STATUS_ACTIVE
+	case dbank.AccountStatusFrozen:
+		return bank.AccountStatus_ACCOUNT_STATUS_FROZEN
+	case dbank.AccountStatusClosed:
+		return bank.AccountStatus_ACCOUNT_STATUS_CLOSED
+	default:
+		return bank.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+	}
+}
+
+func buildUpdateAccountStatusErrorStatusGrpc(err error, req *bank.UpdateAccountStatusRequest) error {
+	var violation *errdetails.BadRequest_FieldViolation
+	var precondition *errdetails.PreconditionFailure_Violation
+
+	switch {
+	case errors.Is(err, dbank.ErrInvalidAccountStatus):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "status",
+			Description: fmt.Sprintf("Status %v is not ACTIVE, FROZEN or CLOSED", req.Status),
+		}
+	case errors.Is(err, dbank.ErrAccountStatusReasonRequired):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "reason",
+			Description: "Reason is required",
+		}
+	case errors.Is(err, dbank.ErrAccountNotFound):
+		precondition = &errdetails.PreconditionFailure_Violation{
+			Type:        "INVALID_ACCOUNT",
+			Subject:     req.AccountNumber,
+			Description: fmt.Sprintf("account %v not found", req.AccountNumber),
+		}
+	case errors.Is(err, dbank.ErrAccountStatusTransition):
+		precondition = &errdetails.PreconditionFailure_Violation{
+			Type:        "ACCOUNT_STATUS_TRANSITION",
+			Subject:     req.AccountNumber,
+			Description: err.Error(),
+		}
+	case errors.Is(err, dbank.ErrAccountBalanceNotZero):
+		precondition = &errdetails.PreconditionFailure_Violation{
+			Type:        "ACCOUNT_BALANCE_NOT_ZERO",
+			Subject:     req.AccountNumber,
+			Description: "Account balance must be zero before closing the account",
+		}
+	default:
+		return status.New(codes.Unknown, err.Error()).Err()
+	}
+
+	if precondition != nil {
+		s := status.New(codes.FailedPrecondition, err.Error())
+		s, _ = s.WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{precondition},
+		})
+
+		return s.Err()
+	}
+
+	s := status.New(codes.InvalidArgument, err.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{violation},
+	})
+
+	return s.Err()
+}
+
+// buildAccountStatusStatusGrpc returns FailedPrecondition for a transaction refused by the status
+// of an account.
+func buildAccountStatusStatusGrpc(err *dbank.AccountStatusError) error {
+	violationType := "ACCOUNT_CLOSED"
+
+	if err.Status == dbank.AccountStatusFrozen {
+		violationType = "ACCOUNT_FROZEN"
+	}
+
+	s := status.New(codes.FailedPrecondition, err.Error())
+	s, _ = s.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{
+				Type:        violationType,
+				Subject:     err.AccountNumber,
+				Description: fmt.Sprintf("account %v is %v", err.AccountNumber, err.Status),
+			},
+		},
+	})
+
+	return s.Err()
+}
+
+func (a *GrpcAdapter) GetAccountPolicy(ctx context.Context,
+	req *bank.GetAccountPolicyRequest) (*bank.AccountPolicyResponse, error) {
+	if err := a.authorizeAccount(ctx, req.AccountNumber); err != nil {
+		return nil, err
+	}
+
+	portCtx, span := startPortSpan(ctx, "BankService.FindAccountPolicy")
+	policy, err := a.bankService.FindAccountPolicy(portCtx, req.AccountNumber)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildAccountPolicyErrorStatusGrpc(err, req.AccountNumber)
+	}
+
+	return toAccountPolicyResponse(policy), nil
+}
+
+func (a *GrpcAdapter) UpdateAccountPolicy(ctx context.Context,
+	req *bank.UpdateAccountPolicyRequest) (*bank.AccountPolicyResponse, error) {
+	if err := a.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	policy, field, err := fromAccountPolicy(req.Policy)
+
+	if err != nil {
+		return nil, buildInvalidAmountStatusGrpc(field, err)
+	}
+
+	policy.AccountNumber = req.AccountNumber
+	policy.UpdatedBy = accountOwner(ctx)
+
+	portCtx, span := startPortSpan(ctx, "BankService.UpdateAccountPolicy")
+	policy, err = a.bankService.UpdateAccountPolicy(portCtx, policy)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildAccountPolicyErrorStatusGrpc(err, req.AccountNumber)
+	}
+
+	return toAccountPolicyResponse(policy), nil
+}
+
+// fromAccountPolicy converts the limits of p, an unset limit is zero. The limits must share one
+// currency code, or have none. On error it returns the field of the invalid limit.
+func fromAccountPolicy(p *bank.AccountPolicy) (dbank.AccountPolicy, string, error) {
+	var policy dbank.AccountPolicy
+
+	limits := []struct {
+		field  string
+		amount *money.Money
+		dst    *decimal.Decimal
+	}{
+		{"policy.overdraft_limit", p.GetOverdraftLimit(), &policy.OverdraftLimit},
+		{"policy.max_transaction_amount", p.GetMaxTransactionAmount(), &policy.MaxTransactionAmount},
+		{"policy.daily_outgoing_limit", p.GetDailyOutgoingLimit(), &policy.DailyOutgoingLimit},
+		{"policy.monthly_outgoing_limit", p.GetMonthlyOutgoingLimit(), &policy.MonthlyOutgoingLimit},
+	}
+
+	for _, limit := range limits {
+		amount, err := toDecimal(limit.amount)
+
+		if err != nil {
+			return dbank.AccountPolicy{}, limit.field, err
+		}
+
+		if amount.IsNegative() {
+			return dbank.AccountPolicy{}, limit.field, fmt.Errorf("%w : %v", dbank.ErrInvalidAccountPolicy, amount)
+		}
+
+		if cur := limit.amount.GetCurrencyCode(); cur != "" {
+			if policy.Currency != "" && policy.Currency != cur {
+				return dbank.AccountPolicy{}, limit.field, fmt.Errorf("%w : limits in %v and %v",
+					dbank.ErrCurrencyMismatch, policy.Currency, cur)
+			}
+
+			policy.Currency = cur
+		}
+
+		*limit.dst = amount
+	}
+
+	return policy, "", nil
+}
+
+func toAccountPolicyResponse(p dbank.AccountPolicy) *bank.AccountPolicyResponse {
+	res := &bank.AccountPolicyResponse{
+		AccountNumber: p.AccountNumber,
+		Policy: &bank.AccountPolicy{
+			OverdraftLimit:       toMoney(p.OverdraftLimit, p.Currency),
+			MaxTransactionAmount: toMoney(p.MaxTransactionAmount, p.Currency),
+			DailyOutgoingLimit:   toMoney(p.DailyOutgoingLimit, p.Currency),
+			MonthlyOutgoingLimit: toMoney(p.MonthlyOutgoingLimit, p.Currency),
+		},
+	}
+
+	if !p.UpdatedAt.IsZero() {
+		res.UpdatedAt = toDatetime(p.UpdatedAt.UTC())
+	}
+
+	return res
+}
+
+func buildAccountPolicyErrorStatusGrpc(err error, acct string) error {
+	switch {
+	case errors.Is(err, dbank.ErrInvalidAccountPolicy), errors.Is(err, dbank.ErrCurrencyMismatch):
+		return buildInvalidAmountStatusGrpc("policy", err)
+	case errors.Is(err, dbank.ErrAccountNotFound):
+		s := status.New(codes.FailedPrecondition, err.Error())
+		s, _ = s.WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "INVALID_ACCOUNT",
+					Subject:     acct,
+					Description: fmt.Sprintf("account %v not found", acct),
+				},
+			},
+		})
+
+		return s.Err()
+	default:
+		return status.New(codes.Unknown, err.Error()).Err()
+	}
+}
+
+// buildAccountLimitStatusGrpc returns ResourceExhausted for a breached daily or monthly outgoing
+// limit, which frees up with time, and FailedPrecondition for the other limits.
+func buildAccountLimitStatusGrpc(err *dbank.AccountLimitError) error {
+	code := codes.FailedPrecondition
+	var description string
+
+	switch err.Limit {
+	case dbank.LimitOverdraft:
+		description = fmt.Sprintf("Requested amount %v exceeds available balance with overdraft limit %v",
+			err.Requested, err.Allowed)
+	case dbank.LimitMaxTransaction:
+		description = fmt.Sprintf("Requested amount %v exceeds maximum transaction amount %v",
+			err.Requested, err.Allowed)
+	case dbank.LimitDailyOutgoing:
+		code = codes.ResourceExhausted
+		description = fmt.Sprintf("Requested amount %v exceeds daily outgoing limit %v, %v already sent today",
+			err.Requested, err.Allowed, err.Used)
+	case dbank.LimitMonthlyOutgoing:
+		code = codes.ResourceExhausted
+		description = fmt.Sprintf("Requested amount %v exceeds monthly outgoing limit %v, %v already sent this month",
+			err.Requested, err.Allowed, err.Used)
+	}
+
+	s := status.New(code, err.Error())
+	s, _ = s.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{
+			{
+				Subject:     fmt.Sprintf("account:%v/%v", err.AccountNumber, err.Limit),
+				Description: description,
+			},
+		},
+	})
+
+	return s.Err()
+}
+
 func (a *GrpcAdapter) FetchExchangeRates(req *bank.ExchangeRateRequest,
 	stream bank.BankService_FetchExchangeRatesServer) error {
 	context := stream.Context()
 
+	portCtx, span := startPortSpan(context, "BankService.SubscribeExchangeRates")
+	rates, unsubscribe, err := a.bankService.SubscribeExchangeRates(portCtx, req.FromCurrency, req.ToCurrency)
+	endPortSpan(span, err)
+
+	if err != nil {
+		s := status.New(codes.InvalidArgument,
+			"Currency not valid. Please use valid currency for both from and to")
+		s, _ = s.WithDetails(&errdetails.ErrorInfo{
+			Domain: "my-bank-website.com",
+			Reason: "INVALID_CURRENCY",
+			Metadata: map[string]string{
+				"from_currency": req.FromCurrency,
+				"to_currency":   req.ToCurrency,
+			},
+		})
+
+		return s.Err()
+	}
+
+	defer unsubscribe()
+
 	for {
 		select {
 		case <-context.Done():
 			log.Println("Client cancelled stream")
 			return nil
-		default:
-			now := time.Now().Truncate(time.Second)
-			rate, err := a.bankService.FindExchangeRate(req.FromCurrency, req.ToCurrency, now)
-
-			if err != nil {
-				s := status.New(codes.InvalidArgument,
-					"Currency not valid. Please use valid currency for both from and to")
-				s, _ = s.WithDetails(&errdetails.ErrorInfo{
-					Domain: "my-bank-website.com",
-					Reason: "INVALID_CURRENCY",
-					Metadata: map[string]string{
-						"from_currency": req.FromCurrency,
-						"to_currency":   req.ToCurrency,
-					},
-				})
-
-				return s.Err()
-			}
-
-			stream.Send(
+		case <-a.shutdown:
+			return errServerShuttingDown
+		case rate := <-rates:
+			err := stream.Send(
 				&bank.ExchangeRateResponse{
-					FromCurrency: req.FromCurrency,
-					ToCurrency:   req.ToCurrency,
-					Rate:         rate,
-					Timestamp:    now.Format(time.RFC3339),
+					FromCurrency: rate.FromCurrency,
+					ToCurrency:   rate.ToCurrency,
+					Rate:         rate.Rate.InexactFloat64(),
+					Timestamp:    rate.ValidFromTimestamp.Format(time.RFC3339),
+					RateDecimal:  rate.Rate.String(),
 				},
 			)
 
-			log.Printf("Exchange rate sent to client, %v to %v : %v\n", req.FromCurrency,
-				req.ToCurrency, rate)
+			if err != nil {
+				return err
+			}
 
-			time.Sleep(3 * time.Second)
+			log.Printf("Exchange rate sent to client, %v to %v : %v\n", rate.FromCurrency,
+				rate.ToCurrency, rate.Rate)
 		}
 	}
 }
 
-func toTime(dt *datetime.DateTime) (time.Time, error) {
-	if dt == nil {
-		now := time.Now()
+// toDecimal converts google.type.Money into an exact decimal amount. A nil money is zero.
+func toDecimal(m *money.Money) (decimal.Decimal, error) {
+	if m == nil {
+		return decimal.Zero, nil
+	}
 
-		dt = &datetime.DateTime{
-			Year:    int32(now.Year()),
-			Month:   int32(now.Month()),
-			Day:     int32(now.Day()),
-			Hours:   int32(now.Hour()),
-			Minutes: int32(now.Minute()),
-			Seconds: int32(now.Second()),
-			Nanos:   int32(now.Nanosecond()),
-		}
+	if m.Nanos <= -1e9 || m.Nanos >= 1e9 {
+		return decimal.Zero, fmt.Errorf("nanos %v out of range", m.Nanos)
 	}
 
-	res := time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day),
-		int(dt.Hours), int(dt.Minutes), int(dt.Seconds), int(dt.Nanos), time.UTC)
+	if (m.Units > 0 && m.Nanos < 0) || (m.Units < 0 && m.Nanos > 0) {
+		return decimal.Zero, fmt.Errorf("units %v and nanos %v must have the same sign", m.Units, m.Nanos)
+	}
 
-	return res, nil
+	return decimal.New(m.Units, 0).Add(decimal.New(int64(m.Nanos), -9)), nil
+}
+
+func toMoney(d decimal.Decimal, currency string) *money.Money {
+	units := d.Truncate(0)
+
+	return &money.Money{
+		CurrencyCode: currency,
+		Units:        units.IntPart(),
+		Nanos:        int32(d.Sub(units).Shift(9).IntPart()),
+	}
+}
+
+func buildInvalidAmountStatusGrpc(field string, err error) error {
+	s := status.New(codes.InvalidArgument, err.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: fmt.Sprintf("Invalid amount : %v", err),
+			},
+		},
+	})
+
+	return s.Err()
 }
 
 func (a *GrpcAdapter) SummarizeTransactions(stream bank.BankService_SummarizeTransactionsServer) error {
+	context := stream.Context()
+
 	tsum := dbank.TransactionSummary{
 		SummaryOnDate: time.Now(),
-		SumIn:         0,
-		SumOut:        0,
-		SumTotal:      0,
+		SumIn:         decimal.Zero,
+		SumOut:        decimal.Zero,
+		SumTotal:      decimal.Zero,
 	}
 	acct := ""
+	cur := ""
+	locations := map[string]*time.Location{}
+
+	for i := 0; ; i++ {
+		select {
+		case <-a.shutdown:
+			return errServerShuttingDown
+		default:
+		}
 
-	for {
 		req, err := stream.Recv()
 
 		if err == io.EOF {
 			res := bank.TransactionSummary{
-				AccountNumber: acct,
-				SumAmountIn:   tsum.SumIn,
-				SumAmountOut:  tsum.SumOut,
-				SumTotal:      tsum.SumTotal,
-				TransactionDate: &date.Date{
-					Year:  int32(tsum.SummaryOnDate.Year()),
-					Month: int32(tsum.SummaryOnDate.Month()),
-					Day:   int32(tsum.SummaryOnDate.Day()),
-				},
+				AccountNumber:   acct,
+				SumAmountIn:     toMoney(tsum.SumIn, cur),
+				SumAmountOut:    toMoney(tsum.SumOut, cur),
+				SumTotal:        toMoney(tsum.SumTotal, cur),
+				TransactionDate: toDate(tsum.SummaryOnDate),
+				DateSummaries:   make([]*bank.TransactionDateSummary, 0, len(tsum.DateSummaries)),
+			}
+
+			for _, dsum := range tsum.DateSummaries {
+				res.DateSummaries = append(res.DateSummaries, &bank.TransactionDateSummary{
+					ValueDate:    toDate(dsum.ValueDate),
+					SumAmountIn:  toMoney(dsum.SumIn, cur),
+					SumAmountOut: toMoney(dsum.SumOut, cur),
+					SumTotal:     toMoney(dsum.SumTotal, cur),
+				})
 			}
 
 			return stream.SendAndClose(&res)
 		}
 
 		if err != nil {
-			log.Fatalln("Error while reading from client :", err)
+			return streamError("SummarizeTransactions", err)
 		}
 
-		acct = req.AccountNumber
-		ts, err := toTime(req.Timestamp)
-
-		if err != nil {
-			log.Fatalf("Error while parsing timestamp %v : %v", req.Timestamp, err)
+		if err := a.authorizeAccount(context, req.AccountNumber); err != nil {
+			return err
 		}
 
+		// checked before the transaction is written, an unknown type must not be stored
 		ttype := dbank.TransactionTypeUnknown
 
 		if req.Type == bank.TransactionType_TRANSACTION_TYPE_IN {
 			ttype = dbank.TransactionTypeIn
 		} else if req.Type == bank.TransactionType_TRANSACTION_TYPE_OUT {
 			ttype = dbank.TransactionTypeOut
+		} else {
+			return buildInvalidTransactionTypeStatusGrpc(req.Type)
+		}
+
+		acct = req.AccountNumber
+		loc, found := locations[req.AccountNumber]
+
+		if !found {
+			loc, err = a.accountLocation(context, req.AccountNumber)
+
+			if st := canceledStatus(context); err != nil && st != nil {
+				return st
+			}
+
+			if err != nil {
+				return buildInvalidAccountNumberStatusGrpc()
+			}
+
+			locations[req.AccountNumber] = loc
+		}
+
+		ts, err := toTime(req.Timestamp, loc)
+
+		if err != nil {
+			s := status.New(codes.InvalidArgument, err.Error())
+			s, _ = s.WithDetails(&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "timestamp",
+						Description: fmt.Sprintf("Invalid timestamp %v : %v", req.Timestamp, err),
+					},
+				},
+			})
+
+			return s.Err()
+		}
+
+		// an unset timestamp is value dated now, on the business day of the account
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+
+		amount, err := toDecimal(req.Amount)
+
+		if err != nil {
+			return buildInvalidAmountStatusGrpc("amount", err)
+		}
+
+		if cur == "" {
+			cur = req.Amount.GetCurrencyCode()
 		}
 
 		tcur := dbank.Transaction{
-			Amount:          req.Amount,
-			Timestamp:       ts,
+			Amount:          amount,
+			Currency:        req.Amount.GetCurrencyCode(),
+			Timestamp:       ts.In(loc),
 			TransactionType: ttype,
+			Notes:           req.Notes,
+			IdempotencyKey:  idempotencyKey(context, req.IdempotencyKey, i),
 		}
 
-		accountUuid, err := a.bankService.CreateTransaction(req.AccountNumber, tcur)
+		portCtx, span := startPortSpan(context, "BankService.CreateTransaction")
+		accountUuid, err := a.bankService.CreateTransaction(portCtx, req.AccountNumber, tcur)
+		endPortSpan(span, err)
 
-		if err != nil && accountUuid == uuid.Nil {
+		if st := canceledStatus(context); err != nil && st != nil {
+			return st
+		}
+
+		if errors.Is(err, dbank.ErrIdempotencyKeyReused) {
+			return buildIdempotencyKeyReusedStatusGrpc(err, tcur.IdempotencyKey)
+		}
+
+		var statusErr *dbank.AccountStatusError
+
+		if errors.As(err, &statusErr) {
+			return buildAccountStatusStatusGrpc(statusErr)
+		}
+
+		var limitErr *dbank.AccountLimitError
+
+		if errors.As(err, &limitErr) {
+			return buildAccountLimitStatusGrpc(limitErr)
+		}
+
+		if errors.Is(err, dbank.ErrValueDateOutOfRange) {
 			s := status.New(codes.InvalidArgument, err.Error())
 			s, _ = s.WithDetails(&errdetails.BadRequest{
 				FieldViolations: []*errdetails.BadRequest_FieldViolation{
 					{
-						Field:       "account_number",
-						Description: "Invalid account number",
+						Field:       "timestamp",
+						Description: fmt.Sprintf("Value date %v is too far in the past or future", req.Timestamp),
 					},
 				},
 			})
 
 			return s.Err()
+		}
+
+		if err != nil && accountUuid == uuid.Nil {
+			return buildInvalidAccountNumberStatusGrpc()
 		} else if err != nil && accountUuid != uuid.Nil {
 			s := status.New(codes.InvalidArgument, err.Error())
 			s, _ = s.WithDetails(&errdetails.BadRequest{
 				FieldViolations: []*errdetails.BadRequest_FieldViolation{
 					{
 						Field:       "amount",
-						Description: fmt.Sprintf("Requested amount %v exceed available balance", req.Amount),
+						Description: err.Error(),
 					},
 				},
 			})
@@ -189,41 +728,225 @@ func (a *GrpcAdapter) SummarizeTransactions(stream bank.BankService_SummarizeTra
 			return s.Err()
 		}
 
+		portCtx, span = startPortSpan(context, "BankService.CalculateTransactionSummary")
+		err = a.bankService.CalculateTransactionSummary(portCtx, &tsum, tcur)
+		endPortSpan(span, err)
+
+		if errors.Is(err, dbank.ErrInvalidTransactionType) {
+			return buildInvalidTransactionTypeStatusGrpc(req.Type)
+		}
+
 		if err != nil {
-			log.Println("Error while creating transaction :", err)
+			return status.Error(codes.Internal, err.Error())
 		}
+	}
+}
 
-		err = a.bankService.CalculateTransactionSummary(&tsum, tcur)
+func buildInvalidTransactionTypeStatusGrpc(t bank.TransactionType) error {
+	s := status.New(codes.InvalidArgument, dbank.ErrInvalidTransactionType.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "type",
+				Description: fmt.Sprintf("Invalid transaction type %v", t),
+			},
+		},
+	})
 
-		if err != nil {
-			return err
+	return s.Err()
+}
+
+func (a *GrpcAdapter) ListTransactions(ctx context.Context,
+	req *bank.ListTransactionsRequest) (*bank.ListTransactionsResponse, error) {
+	if err := a.authorizeAccount(ctx, req.AccountNumber); err != nil {
+		return nil, err
+	}
+
+	loc, err := a.accountLocation(ctx, req.AccountNumber)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildListTransactionsErrorStatusGrpc(err, req)
+	}
+
+	// dates are business days of the account
+	fromDay, err := toStartOfDay(req.FromDate, loc)
+
+	if err != nil {
+		return nil, buildInvalidDateStatusGrpc("from_date", err)
+	}
+
+	toDay, err := toStartOfDay(req.ToDate, loc)
+
+	if err != nil {
+		return nil, buildInvalidDateStatusGrpc("to_date", err)
+	}
+
+	q := dbank.TransactionQuery{
+		AccountNumber: req.AccountNumber,
+		FromDate:      fromDay,
+		ToDate:        toDay,
+		PageSize:      int(req.PageSize),
+		PageToken:     req.PageToken,
+	}
+
+	switch req.Type {
+	case bank.TransactionType_TRANSACTION_TYPE_IN:
+		q.TransactionType = dbank.TransactionTypeIn
+	case bank.TransactionType_TRANSACTION_TYPE_OUT:
+		q.TransactionType = dbank.TransactionTypeOut
+	}
+
+	portCtx, span := startPortSpan(ctx, "BankService.ListTransactions")
+	page, err := a.bankService.ListTransactions(portCtx, q)
+	endPortSpan(span, err)
+
+	if st := canceledStatus(ctx); err != nil && st != nil {
+		return nil, st
+	}
+
+	if err != nil {
+		return nil, buildListTransactionsErrorStatusGrpc(err, req)
+	}
+
+	res := &bank.ListTransactionsResponse{
+		Transactions:  make([]*bank.Transaction, 0, len(page.Transactions)),
+		NextPageToken: page.NextPageToken,
+	}
+
+	for _, t := range page.Transactions {
+		ttype := bank.TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+
+		if t.TransactionType == dbank.TransactionTypeIn {
+			ttype = bank.TransactionType_TRANSACTION_TYPE_IN
+		} else if t.TransactionType == dbank.TransactionTypeOut {
+			ttype = bank.TransactionType_TRANSACTION_TYPE_OUT
 		}
+
+		res.Transactions = append(res.Transactions, &bank.Transaction{
+			AccountNumber:    req.AccountNumber,
+			Type:             ttype,
+			Timestamp:        toDatetime(t.Timestamp.In(loc)),
+			Amount:           toMoney(t.Amount, t.Currency),
+			Notes:            t.Notes,
+			IdempotencyKey:   t.IdempotencyKey,
+			BookingTimestamp: toDatetime(t.BookingTimestamp.In(loc)),
+		})
 	}
+
+	return res, nil
 }
 
-func currentDatetime() *datetime.DateTime {
-	now := time.Now()
+func buildInvalidDateStatusGrpc(field string, err error) error {
+	s := status.New(codes.InvalidArgument, err.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: "Date must be a full calendar date",
+			},
+		},
+	})
+
+	return s.Err()
+}
+
+func buildInvalidAccountNumberStatusGrpc() error {
+	s := status.New(codes.InvalidArgument, "invalid account number")
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "account_number",
+				Description: "Invalid account number",
+			},
+		},
+	})
+
+	return s.Err()
+}
+
+func buildListTransactionsErrorStatusGrpc(err error, req *bank.ListTransactionsRequest) error {
+	var violation *errdetails.BadRequest_FieldViolation
 
-	return &datetime.DateTime{
-		Year:       int32(now.Year()),
-		Month:      int32(now.Month()),
-		Day:        int32(now.Day()),
-		Hours:      int32(now.Hour()),
-		Minutes:    int32(now.Minute()),
-		Seconds:    int32(now.Second()),
-		Nanos:      int32(now.Second()),
-		TimeOffset: &datetime.DateTime_UtcOffset{},
+	switch {
+	case errors.Is(err, dbank.ErrAccountNotFound):
+		s := status.New(codes.FailedPrecondition, err.Error())
+		s, _ = s.WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "INVALID_ACCOUNT",
+					Subject:     "Account not found",
+					Description: fmt.Sprintf("account %v not found", req.AccountNumber),
+				},
+			},
+		})
+
+		return s.Err()
+	case errors.Is(err, dbank.ErrInvalidDateRange):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "to_date",
+			Description: "To date must be on or after from date",
+		}
+	case errors.Is(err, dbank.ErrInvalidPageToken):
+		violation = &errdetails.BadRequest_FieldViolation{
+			Field:       "page_token",
+			Description: "Page token must be a next_page_token from a previous response with the same filters",
+		}
+	default:
+		return status.New(codes.Internal, err.Error()).Err()
+	}
+
+	s := status.New(codes.InvalidArgument, err.Error())
+	s, _ = s.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{violation},
+	})
+
+	return s.Err()
+}
+
+// idempotencyKey returns the idempotency key of the i-th message on a stream. The key set on
+// the message wins; otherwise the "idempotency-key" request metadata is suffixed with i, so a
+// client can retry a whole stream with one key.
+func idempotencyKey(ctx context.Context, messageKey string, i int) string {
+	if messageKey != "" {
+		return messageKey
 	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyMetadata); len(keys) > 0 && keys[0] != "" {
+			return fmt.Sprintf("%v-%d", keys[0], i)
+		}
+	}
+
+	return ""
+}
+
+func buildIdempotencyKeyReusedStatusGrpc(err error, key string) error {
+	s := status.New(codes.AlreadyExists, err.Error())
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: "my-bank-website.com",
+		Reason: "IDEMPOTENCY_KEY_REUSED",
+		Metadata: map[string]string{
+			"idempotency_key": key,
+		},
+	})
+
+	return s.Err()
 }
 
 func (a *GrpcAdapter) TransferMultiple(stream bank.BankService_TransferMultipleServer) error {
 	context := stream.Context()
 
-	for {
+	for i := 0; ; i++ {
 		select {
 		case <-context.Done():
 			log.Println("Client cancelled stream")
 			return nil
+		case <-a.shutdown:
+			return errServerShuttingDown
 		default:
 			req, err := stream.Recv()
 
@@ -232,31 +955,60 @@ func (a *GrpcAdapter) TransferMultiple(stream bank.BankService_TransferMultipleS
 			}
 
 			if err != nil {
-				log.Fatalln("Error while reading from client :", err)
+				return streamError("TransferMultiple", err)
+			}
+
+			if err := a.authorizeAccount(context, req.FromAccountNumber); err != nil {
+				return err
+			}
+
+			amount, err := toDecimal(req.Amount)
+
+			if err != nil {
+				return buildInvalidAmountStatusGrpc("amount", err)
+			}
+
+			if cur := req.Amount.GetCurrencyCode(); cur != "" && cur != req.Currency {
+				return buildInvalidAmountStatusGrpc("amount",
+					fmt.Errorf("%w : amount in %v, transfer in %v", dbank.ErrCurrencyMismatch, cur, req.Currency))
 			}
 
 			tt := dbank.TransferTransaction{
 				FromAccountNumber: req.FromAccountNumber,
 				ToAccountNumber:   req.ToAccountNumber,
 				Currency:          req.Currency,
-				Amount:            req.Amount,
+				Amount:            amount,
+				IdempotencyKey:    idempotencyKey(context, req.IdempotencyKey, i),
 			}
 
-			_, transferSuccess, err := a.bankService.Transfer(tt)
+			portCtx, span := startPortSpan(context, "BankService.Transfer")
+			transferResult, err := a.bankService.Transfer(portCtx, tt)
+			endPortSpan(span, err)
+
+			observeTransfer(transferResult, err)
+
+			if st := canceledStatus(context); err != nil && st != nil {
+				return st
+			}
+
+			if errors.Is(err, dbank.ErrIdempotencyKeyReused) {
+				return buildIdempotencyKeyReusedStatusGrpc(err, tt.IdempotencyKey)
+			}
 
 			if err != nil {
-				return buildTransferErrorStatusGrpc(err, *req)
+				return buildTransferErrorStatusGrpc(err, req)
 			}
 
 			res := bank.TransferResponse{
 				FromAccountNumber: req.FromAccountNumber,
 				ToAccountNumber:   req.ToAccountNumber,
 				Currency:          req.Currency,
-				Amount:            req.Amount,
-				Timestamp:         currentDatetime(),
+				Amount:            toMoney(dbank.RoundAmount(amount), req.Currency),
+				Timestamp:         toDatetime(transferResult.TransferTimestamp.UTC()),
+				ExchangeRate:      transferResult.ExchangeRate.String(),
 			}
 
-			if transferSuccess {
+			if transferResult.TransferSuccess {
 				res.Status = bank.TransferStatus_TRANSFER_STATUS_SUCCESS
 			} else {
 				res.Status = bank.TransferStatus_TRANSFER_STATUS_FAILED
@@ -265,14 +1017,104 @@ func (a *GrpcAdapter) TransferMultiple(stream bank.BankService_TransferMultipleS
 			err = stream.Send(&res)
 
 			if err != nil {
-				log.Fatalln("Error while sending response to client :", err)
+				return streamError("TransferMultiple", err)
 			}
 		}
 	}
 }
 
-func buildTransferErrorStatusGrpc(err error, req bank.TransferRequest) error {
+// observeTransfer counts the transfer by result, and by the error types told apart by
+// buildTransferErrorStatusGrpc.
+func observeTransfer(res dbank.TransferResult, err error) {
+	if err == nil {
+		result := "success"
+
+		if !res.TransferSuccess {
+			result = "failed"
+		}
+
+		metrics.BankTransfers.WithLabelValues(result, "").Inc()
+		return
+	}
+
+	var errorType string
+
+	switch {
+	case errors.Is(err, dbank.ErrIdempotencyKeyReused):
+		errorType = "idempotency_key_reused"
+	case errors.Is(err, dbank.ErrAccountFrozen):
+		errorType = "account_frozen"
+	case errors.Is(err, dbank.ErrAccountClosed):
+		errorType = "account_closed"
+	case errors.Is(err, dbank.ErrInsufficientBalance):
+		errorType = "insufficient_balance"
+	case errors.Is(err, dbank.ErrTransactionLimitExceeded):
+		errorType = "transaction_limit_exceeded"
+	case errors.Is(err, dbank.ErrOutgoingLimitExceeded):
+		errorType = "outgoing_limit_exceeded"
+	case errors.Is(err, dbank.ErrInvalidAmount):
+		errorType = "invalid_amount"
+	case errors.Is(err, dbank.ErrCurrencyMismatch):
+		errorType = "currency_mismatch"
+	case errors.Is(err, dbank.ErrExchangeRateNotFound):
+		errorType = "exchange_rate_not_found"
+	case errors.Is(err, dbank.ErrTransferSameAccount):
+		errorType = "same_account"
+	case errors.Is(err, dbank.ErrTransferSourceAccountNotFound):
+		errorType = "source_account_not_found"
+	case errors.Is(err, dbank.ErrTransferDestinationAccountNotFound):
+		errorType = "destination_account_not_found"
+	case errors.Is(err, dbank.ErrTransferRecordFailed):
+		errorType = "record_failed"
+	case errors.Is(err, dbank.ErrTransferTransactionPair):
+		errorType = "transaction_pair_failed"
+	default:
+		errorType = "unknown"
+	}
+
+	metrics.BankTransfers.WithLabelValues("error", errorType).Inc()
+}
+
+func buildTransferErrorStatusGrpc(err error, req *bank.TransferRequest) error {
+	amount, _ := toDecimal(req.Amount)
+
+	var statusErr *dbank.AccountStatusError
+	var limitErr *dbank.AccountLimitError
+
 	switch {
+	case errors.As(err, &statusErr):
+		return buildAccountStatusStatusGrpc(statusErr)
+	case errors.As(err, &limitErr):
+		return buildAccountLimitStatusGrpc(limitErr)
+	case errors.Is(err, dbank.ErrInvalidAmount):
+		return buildInvalidAmountStatusGrpc("amount", err)
+	case errors.Is(err, dbank.ErrCurrencyMismatch):
+		return buildInvalidAmountStatusGrpc("currency", err)
+	case errors.Is(err, dbank.ErrTransferSameAccount):
+		s := status.New(codes.InvalidArgument, err.Error())
+		s, _ = s.WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "to_account_number",
+					Description: fmt.Sprintf("Can't transfer from account %v to itself", req.FromAccountNumber),
+				},
+			},
+		})
+
+		return s.Err()
+	case errors.Is(err, dbank.ErrExchangeRateNotFound):
+		s := status.New(codes.FailedPrecondition, err.Error())
+		s, _ = s.WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "EXCHANGE_RATE",
+					Subject:     "Exchange rate not found",
+					Description: err.Error(),
+				},
+			},
+		})
+
+		return s.Err()
 	case errors.Is(err, dbank.ErrTransferSourceAccountNotFound):
 		s := status.New(codes.FailedPrecondition, err.Error())
 		s, _ = s.WithDetails(&errdetails.PreconditionFailure{
@@ -320,7 +1162,7 @@ func buildTransferErrorStatusGrpc(err error, req bank.TransferRequest) error {
 				"from_account": req.FromAccountNumber,
 				"to_account":   req.ToAccountNumber,
 				"currency":     req.Currency,
-				"amount":       fmt.Sprintf("%f", req.Amount),
+				"amount":       amount.String(),
 			},
 		})
 
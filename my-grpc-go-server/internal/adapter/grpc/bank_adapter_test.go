@@ -0,0 +1,202 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTransferErrorStatus(t *testing.T) {
+	req := &bank.TransferRequest{FromAccountNumber: "A1", ToAccountNumber: "A1", Currency: "USD",
+		Amount: usd(10)}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCode  codes.Code
+		wantField string
+	}{
+		{"same account", fmt.Errorf("%w : A1", dbank.ErrTransferSameAccount), codes.InvalidArgument,
+			"to_account_number"},
+		{"invalid amount", dbank.ErrInvalidAmount, codes.InvalidArgument, "amount"},
+		{"source account", dbank.ErrTransferSourceAccountNotFound, codes.FailedPrecondition, ""},
+		{"unknown", errors.New("connection reset"), codes.Unknown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(buildTransferErrorStatusGrpc(tt.err, req))
+
+			if st.Code() != tt.wantCode {
+				t.Fatalf("status = %v, want %v", st, tt.wantCode)
+			}
+
+			field := ""
+
+			for _, d := range st.Details() {
+				if badRequest, ok := d.(*errdetails.BadRequest); ok {
+					field = badRequest.FieldViolations[0].Field
+				}
+			}
+
+			if field != tt.wantField {
+				t.Errorf("field violation = %q, want %q", field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTransferExchangeRate(t *testing.T) {
+	tests := []struct {
+		name string
+		rate string
+	}{
+		{"same currency", "1"},
+		// not representable as a double
+		{"cross currency", "0.000064516129032258064516"},
+		{"large", "15873.0158730158730159"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ports := newFakePorts()
+			ports.exchangeRate = decimal.RequireFromString(tt.rate)
+			conn := startTestServer(t, ports)()
+
+			stream, err := bank.NewBankServiceClient(conn).TransferMultiple(context.Background())
+
+			if err != nil {
+				t.Fatalf("TransferMultiple : %v", err)
+			}
+
+			if err := stream.Send(&bank.TransferRequest{FromAccountNumber: "A1", ToAccountNumber: "A2",
+				Currency: "USD", Amount: usd(10)}); err != nil {
+				t.Fatalf("Send : %v", err)
+			}
+
+			res, err := stream.Recv()
+
+			if err != nil {
+				t.Fatalf("Recv : %v", err)
+			}
+
+			if res.ExchangeRate != tt.rate {
+				t.Errorf("exchange rate = %q, want %q", res.ExchangeRate, tt.rate)
+			}
+
+			stream.CloseSend()
+		})
+	}
+}
+
+func TestFetchExchangeRatesExactRate(t *testing.T) {
+	ports := newFakePorts()
+	// not representable as a double
+	ports.exchangeRate = decimal.RequireFromString("0.000064516129032258064516")
+	conn := startTestServer(t, ports)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := bank.NewBankServiceClient(conn).FetchExchangeRates(ctx,
+		&bank.ExchangeRateRequest{FromCurrency: "IDR", ToCurrency: "USD"})
+
+	if err != nil {
+		t.Fatalf("FetchExchangeRates : %v", err)
+	}
+
+	res, err := stream.Recv()
+
+	if err != nil {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	if res.RateDecimal != ports.exchangeRate.String() {
+		t.Errorf("rate = %q, want %q", res.RateDecimal, ports.exchangeRate.String())
+	}
+
+	// kept for older clients
+	if res.Rate != ports.exchangeRate.InexactFloat64() {
+		t.Errorf("double rate = %v, want %v", res.Rate, ports.exchangeRate.InexactFloat64())
+	}
+}
+
+func TestSummarizeTransactionsInvalidType(t *testing.T) {
+	for _, ttype := range []bank.TransactionType{bank.TransactionType_TRANSACTION_TYPE_UNSPECIFIED,
+		bank.TransactionType(99)} {
+		t.Run(ttype.String(), func(t *testing.T) {
+			ports := newFakePorts()
+			conn := startTestServer(t, ports)()
+
+			stream, err := bank.NewBankServiceClient(conn).SummarizeTransactions(context.Background())
+
+			if err != nil {
+				t.Fatalf("SummarizeTransactions : %v", err)
+			}
+
+			for _, tt := range []bank.TransactionType{bank.TransactionType_TRANSACTION_TYPE_IN, ttype} {
+				if err := stream.Send(&bank.Transaction{AccountNumber: "A1", Type: tt, Amount: usd(10)}); err != nil {
+					t.Fatalf("Send : %v", err)
+				}
+			}
+
+			_, err = stream.CloseAndRecv()
+
+			if st := status.Convert(err); st.Code() != codes.InvalidArgument {
+				t.Errorf("SummarizeTransactions = %v, want %v", err, codes.InvalidArgument)
+			}
+
+			// only the valid transaction was written
+			if created := ports.created.Load(); created != 1 {
+				t.Errorf("CreateTransaction calls = %v, want 1", created)
+			}
+		})
+	}
+}
+
+func TestListTransactionsErrorStatus(t *testing.T) {
+	req := &bank.ListTransactionsRequest{AccountNumber: "A1", PageToken: "x"}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCode  codes.Code
+		wantField string
+	}{
+		{"invalid page token", fmt.Errorf("%w : malformed token x", dbank.ErrInvalidPageToken),
+			codes.InvalidArgument, "page_token"},
+		{"invalid date range", dbank.ErrInvalidDateRange, codes.InvalidArgument, "to_date"},
+		{"account not found", fmt.Errorf("%w : A1", dbank.ErrAccountNotFound), codes.FailedPrecondition, ""},
+		{"unknown", errors.New("connection reset"), codes.Internal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(buildListTransactionsErrorStatusGrpc(tt.err, req))
+
+			if st.Code() != tt.wantCode {
+				t.Fatalf("status = %v, want %v", st, tt.wantCode)
+			}
+
+			field := ""
+
+			for _, d := range st.Details() {
+				if badRequest, ok := d.(*errdetails.BadRequest); ok {
+					field = badRequest.FieldViolations[0].Field
+				}
+			}
+
+			if field != tt.wantField {
+				t.Errorf("field violation = %q, want %q", field, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// maxUtcOffset is the largest UTC offset google.type.DateTime allows.
+const maxUtcOffset = 18 * time.Hour
+
+// accountLocation returns the time zone defining the business day of the account acct.
+func (a *GrpcAdapter) accountLocation(ctx context.Context, acct string) (*time.Location, error) {
+	portCtx, span := startPortSpan(ctx, "BankService.FindAccountLocation")
+	loc, err := a.bankService.FindAccountLocation(portCtx, acct)
+	endPortSpan(span, err)
+
+	return loc, err
+}
+
+// toTime converts google.type.DateTime to a time. The UTC offset or IANA time zone of dt wins;
+// a civil date time without either is read in loc. A nil dt is the zero time.
+//
+// A local time repeated when clocks go back is its first occurrence. A local time skipped when
+// clocks go forward is moved forward by the length of the gap, e.g. 02:30 becomes 03:30 on the
+// day New York switches to daylight saving time.
+func toTime(dt *datetime.DateTime, loc *time.Location) (time.Time, error) {
+	if dt == nil {
+		return time.Time{}, nil
+	}
+
+	if err := validateCivilDate(dt.Year, dt.Month, dt.Day); err != nil {
+		return time.Time{}, err
+	}
+
+	if dt.Hours < 0 || dt.Hours > 23 || dt.Minutes < 0 || dt.Minutes > 59 || dt.Seconds < 0 ||
+		dt.Seconds > 59 || dt.Nanos < 0 || dt.Nanos > 999999999 {
+		return time.Time{}, fmt.Errorf("invalid time %02d:%02d:%02d.%09d", dt.Hours, dt.Minutes,
+			dt.Seconds, dt.Nanos)
+	}
+
+	switch offset := dt.TimeOffset.(type) {
+	case *datetime.DateTime_UtcOffset:
+		if offset.UtcOffset != nil {
+			if err := offset.UtcOffset.CheckValid(); err != nil {
+				return time.Time{}, fmt.Errorf("invalid UTC offset : %v", err)
+			}
+
+			d := offset.UtcOffset.AsDuration()
+
+			if d%time.Second != 0 || d < -maxUtcOffset || d > maxUtcOffset {
+				return time.Time{}, fmt.Errorf("UTC offset %v must be whole seconds between -18h and +18h", d)
+			}
+
+			loc = time.FixedZone("", int(d/time.Second))
+		} else {
+			loc = time.UTC
+		}
+	case *datetime.DateTime_TimeZone:
+		tz, err := dbank.LoadTimeZone(offset.TimeZone.GetId())
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		loc = tz
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return dbank.CivilTime(int(dt.Year), time.Month(dt.Month), int(dt.Day), int(dt.Hours),
+		int(dt.Minutes), int(dt.Seconds), int(dt.Nanos), loc), nil
+}
+
+// toDatetime converts t to google.type.DateTime in the location of t. A time in an IANA
+// location keeps its time zone id, any other time gets its UTC offset.
+func toDatetime(t time.Time) *datetime.DateTime {
+	dt := &datetime.DateTime{
+		Year:    int32(t.Year()),
+		Month:   int32(t.Month()),
+		Day:     int32(t.Day()),
+		Hours:   int32(t.Hour()),
+		Minutes: int32(t.Minute()),
+		Seconds: int32(t.Second()),
+		Nanos:   int32(t.Nanosecond()),
+	}
+
+	switch name := t.Location().String(); name {
+	case "", "UTC", "Local":
+		_, offset := t.Zone()
+
+		dt.TimeOffset = &datetime.DateTime_UtcOffset{
+			UtcOffset: durationpb.New(time.Duration(offset) * time.Second),
+		}
+	default:
+		dt.TimeOffset = &datetime.DateTime_TimeZone{
+			TimeZone: &datetime.TimeZone{Id: name},
+		}
+	}
+
+	return dt
+}
+
+// toDate returns the date of t in the location of t.
+func toDate(t time.Time) *date.Date {
+	return &date.Date{
+		Year:  int32(t.Year()),
+		Month: int32(t.Month()),
+		Day:   int32(t.Day()),
+	}
+}
+
+// toStartOfDay converts google.type.Date to the start of that day in loc, a nil date is the
+// zero time. A day whose midnight is skipped by a clock change starts after the gap.
+func toStartOfDay(d *date.Date, loc *time.Location) (time.Time, error) {
+	if d == nil {
+		return time.Time{}, nil
+	}
+
+	if err := validateCivilDate(d.Year, d.Month, d.Day); err != nil {
+		return time.Time{}, err
+	}
+
+	return dbank.StartOfDay(int(d.Year), time.Month(d.Month), int(d.Day), loc), nil
+}
+
+// validateCivilDate refuses the partial dates google.type.Date allows, e.g. a zero year, and
+// days not in the month.
+func validateCivilDate(year int32, month int32, day int32) error {
+	if year < 1 || year > 9999 || month < 1 || month > 12 || day < 1 {
+		return fmt.Errorf("invalid date %04d-%02d-%02d", year, month, day)
+	}
+
+	if t := time.Date(int(year), time.Month(month), int(day), 0, 0, 0, 0, time.UTC); t.Day() != int(day) {
+		return fmt.Errorf("invalid date %04d-%02d-%02d", year, month, day)
+	}
+
+	return nil
+}
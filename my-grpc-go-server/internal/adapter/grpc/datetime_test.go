@@ -0,0 +1,243 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		t.Fatalf("LoadLocation(%v) : %v", name, err)
+	}
+
+	return loc
+}
+
+func civilDatetime(year int32, month int32, day int32, hours int32, minutes int32) *datetime.DateTime {
+	return &datetime.DateTime{Year: year, Month: month, Day: day, Hours: hours, Minutes: minutes}
+}
+
+func withUtcOffset(dt *datetime.DateTime, offset *durationpb.Duration) *datetime.DateTime {
+	dt.TimeOffset = &datetime.DateTime_UtcOffset{UtcOffset: offset}
+	return dt
+}
+
+func withTimeZone(dt *datetime.DateTime, id string) *datetime.DateTime {
+	dt.TimeOffset = &datetime.DateTime_TimeZone{TimeZone: &datetime.TimeZone{Id: id}}
+	return dt
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestToTime(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+
+	tests := []struct {
+		name string
+		dt   *datetime.DateTime
+		loc  *time.Location
+		want time.Time
+	}{
+		{"nil", nil, time.UTC, time.Time{}},
+		{"civil in default location", civilDatetime(2026, 7, 1, 12, 0), jakarta,
+			time.Date(2026, 7, 1, 5, 0, 0, 0, time.UTC)},
+		{"civil without location", civilDatetime(2026, 7, 1, 12, 0), nil,
+			time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{"nil offset is utc", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), nil), jakarta,
+			time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{"zero offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(0)), jakarta,
+			time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{"negative offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(-4*time.Hour)), jakarta,
+			time.Date(2026, 7, 1, 16, 0, 0, 0, time.UTC)},
+		{"half hour offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(5*time.Hour+30*time.Minute)),
+			jakarta, time.Date(2026, 7, 1, 6, 30, 0, 0, time.UTC)},
+		{"maximum offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(18*time.Hour)), jakarta,
+			time.Date(2026, 6, 30, 18, 0, 0, 0, time.UTC)},
+
+		// an explicit offset is the same instant as the zone it matches
+		{"zone summer", withTimeZone(civilDatetime(2026, 7, 1, 12, 0), "America/New_York"), jakarta,
+			time.Date(2026, 7, 1, 16, 0, 0, 0, time.UTC)},
+		{"zone winter", withTimeZone(civilDatetime(2026, 1, 15, 12, 0), "America/New_York"), jakarta,
+			time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC)},
+
+		// an explicit offset is taken as is, even for a wall clock skipped in the zone of loc
+		{"offset in zone gap", withUtcOffset(civilDatetime(2026, 3, 8, 2, 30), durationpb.New(-5*time.Hour)),
+			mustLoadLocation(t, "America/New_York"), time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC)},
+		{"zone gap", withTimeZone(civilDatetime(2026, 3, 8, 2, 30), "America/New_York"), time.UTC,
+			time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC)},
+		{"zone overlap", withTimeZone(civilDatetime(2026, 11, 1, 1, 30), "America/New_York"), time.UTC,
+			time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"lord howe gap", withTimeZone(civilDatetime(2026, 10, 4, 2, 15), "Australia/Lord_Howe"), time.UTC,
+			time.Date(2026, 10, 3, 15, 45, 0, 0, time.UTC)},
+		{"lord howe overlap", withTimeZone(civilDatetime(2026, 4, 5, 1, 45), "Australia/Lord_Howe"), time.UTC,
+			time.Date(2026, 4, 4, 14, 45, 0, 0, time.UTC)},
+		{"santiago midnight gap", civilDatetime(2026, 9, 6, 0, 0), mustLoadLocation(t, "America/Santiago"),
+			time.Date(2026, 9, 6, 4, 0, 0, 0, time.UTC)},
+		{"santiago overlap", withTimeZone(civilDatetime(2026, 4, 4, 23, 30), "America/Santiago"), time.UTC,
+			time.Date(2026, 4, 5, 2, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toTime(tt.dt, tt.loc)
+
+			if err != nil {
+				t.Fatalf("toTime : %v", err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("toTime = %v (%v), want %v", got.UTC(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToTimeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		dt   *datetime.DateTime
+	}{
+		{"zero date", &datetime.DateTime{}},
+		{"zero year", civilDatetime(0, 7, 1, 12, 0)},
+		{"month 13", civilDatetime(2026, 13, 1, 12, 0)},
+		{"day 0", civilDatetime(2026, 7, 0, 12, 0)},
+		{"30 february", civilDatetime(2026, 2, 30, 12, 0)},
+		{"29 february of a common year", civilDatetime(2026, 2, 29, 12, 0)},
+		{"hour 24", civilDatetime(2026, 7, 1, 24, 0)},
+		{"negative minutes", civilDatetime(2026, 7, 1, 12, -1)},
+		{"leap second", &datetime.DateTime{Year: 2026, Month: 7, Day: 1, Seconds: 60}},
+		{"nanos overflow", &datetime.DateTime{Year: 2026, Month: 7, Day: 1, Nanos: 1e9}},
+		{"offset over 18 hours", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(18*time.Hour+time.Second))},
+		{"offset under -18 hours", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(-19*time.Hour))},
+		{"fractional offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), durationpb.New(time.Hour+time.Millisecond))},
+		{"malformed offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0),
+			&durationpb.Duration{Seconds: 3600, Nanos: -1})},
+		{"huge offset", withUtcOffset(civilDatetime(2026, 7, 1, 12, 0), &durationpb.Duration{Seconds: 1 << 62})},
+		{"unknown zone", withTimeZone(civilDatetime(2026, 7, 1, 12, 0), "Mars/Olympus_Mons")},
+		{"empty zone", withTimeZone(civilDatetime(2026, 7, 1, 12, 0), "")},
+		{"local zone", withTimeZone(civilDatetime(2026, 7, 1, 12, 0), "Local")},
+		{"nil zone", &datetime.DateTime{Year: 2026, Month: 7, Day: 1,
+			TimeOffset: &datetime.DateTime_TimeZone{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := toTime(tt.dt, time.UTC); err == nil {
+				t.Errorf("toTime = %v, want error", got)
+			}
+		})
+	}
+}
+
+func TestToDatetime(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		wantOffset *time.Duration
+		wantZone   string
+	}{
+		{"utc", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), durationPtr(0), ""},
+		{"fixed zone", time.Date(2026, 7, 1, 12, 0, 0, 0, time.FixedZone("", -4*3600)), durationPtr(-4 * time.Hour), ""},
+		{"iana zone", time.Date(2026, 7, 1, 12, 0, 0, 0, newYork), nil, "America/New_York"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := toDatetime(tt.t)
+
+			if dt.Year != 2026 || dt.Month != 7 || dt.Day != 1 || dt.Hours != 12 || dt.Minutes != 0 {
+				t.Errorf("toDatetime = %v, want 2026-07-01 12:00", dt)
+			}
+
+			if tt.wantOffset != nil && dt.GetUtcOffset().AsDuration() != *tt.wantOffset {
+				t.Errorf("toDatetime offset = %v, want %v", dt.GetUtcOffset(), *tt.wantOffset)
+			}
+
+			if tt.wantZone != "" && dt.GetTimeZone().GetId() != tt.wantZone {
+				t.Errorf("toDatetime zone = %v, want %v", dt.GetTimeZone(), tt.wantZone)
+			}
+
+			// the conversion back is the same instant
+			back, err := toTime(dt, nil)
+
+			if err != nil {
+				t.Fatalf("toTime : %v", err)
+			}
+
+			if !back.Equal(tt.t) {
+				t.Errorf("toTime(toDatetime(%v)) = %v", tt.t, back)
+			}
+		})
+	}
+}
+
+func TestToDatetimeOverlapRoundTrip(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	// the second 01:30 of 1 November only survives the round trip as a UTC offset
+	second := time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)
+	back, err := toTime(toDatetime(second), nil)
+
+	if err != nil || !back.Equal(second) {
+		t.Errorf("toTime(toDatetime(%v)) = %v, %v", second, back, err)
+	}
+
+	first := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(newYork)
+	back, err = toTime(toDatetime(first), nil)
+
+	if err != nil || !back.Equal(first) {
+		t.Errorf("toTime(toDatetime(%v)) = %v, %v", first, back, err)
+	}
+}
+
+func TestToStartOfDay(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       *date.Date
+		zone    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"nil", nil, "UTC", time.Time{}, false},
+		{"utc", &date.Date{Year: 2026, Month: 7, Day: 1}, "UTC", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), false},
+		{"jakarta", &date.Date{Year: 2026, Month: 7, Day: 1}, "Asia/Jakarta",
+			time.Date(2026, 6, 30, 17, 0, 0, 0, time.UTC), false},
+		{"santiago skipped midnight", &date.Date{Year: 2026, Month: 9, Day: 6}, "America/Santiago",
+			time.Date(2026, 9, 6, 4, 0, 0, 0, time.UTC), false},
+		{"lord howe gap day", &date.Date{Year: 2026, Month: 10, Day: 4}, "Australia/Lord_Howe",
+			time.Date(2026, 10, 3, 13, 30, 0, 0, time.UTC), false},
+		{"leap day", &date.Date{Year: 2028, Month: 2, Day: 29}, "UTC", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"not a leap day", &date.Date{Year: 2026, Month: 2, Day: 29}, "UTC", time.Time{}, true},
+		{"partial date", &date.Date{Year: 2026, Month: 7}, "UTC", time.Time{}, true},
+		{"year only", &date.Date{Year: 2026}, "UTC", time.Time{}, true},
+		{"year 10000", &date.Date{Year: 10000, Month: 1, Day: 1}, "UTC", time.Time{}, true},
+		{"negative day", &date.Date{Year: 2026, Month: 1, Day: -1}, "UTC", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toStartOfDay(tt.d, mustLoadLocation(t, tt.zone))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toStartOfDay error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("toStartOfDay = %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
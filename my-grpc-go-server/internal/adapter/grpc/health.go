@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
+	resl "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckTimeout bounds a single database check, so a hanging database turns the bank
+// service NOT_SERVING instead of delaying the next check.
+const healthCheckTimeout = 3 * time.Second
+
+// services without dependencies, serving as long as the server runs
+var alwaysServingServices = []string{
+	hello.HelloService_ServiceDesc.ServiceName,
+	resl.ResiliencyService_ServiceDesc.ServiceName,
+	resl.ResiliencyWithMetadataService_ServiceDesc.ServiceName,
+}
+
+func newHealthServer() *health.Server {
+	hs := health.NewServer()
+
+	for _, service := range alwaysServingServices {
+		hs.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	// unknown until the first database check
+	hs.SetServingStatus(bank.BankService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return hs
+}
+
+// watchBankHealth checks the bank service health with bankHealth every interval until ctx is
+// done. The overall server status (empty service name) follows the bank status, since no other
+// service needs a dependency.
+func (a *GrpcAdapter) watchBankHealth(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	serving, checked := false, false
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := a.bankHealth.CheckHealth(checkCtx)
+		cancel()
+
+		// the server may be shutting down meanwhile, its NOT_SERVING status must stay
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil && (serving || !checked) {
+			log.Println("Bank service not serving :", err)
+		} else if err == nil && !serving {
+			log.Println("Bank service serving")
+		}
+
+		serving, checked = err == nil, true
+
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+
+		if serving {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+
+		a.health.SetServingStatus(bank.BankService_ServiceDesc.ServiceName, status)
+		a.health.SetServingStatus("", status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
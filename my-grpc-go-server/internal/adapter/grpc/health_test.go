@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakePinger answers each health check with the next error sent on results, waiting for it.
+type fakePinger struct {
+	results chan error
+}
+
+func (p *fakePinger) CheckHealth(ctx context.Context) error {
+	select {
+	case err := <-p.results:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// servingStatus returns the health status of service.
+func servingStatus(t *testing.T, a *GrpcAdapter, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	res, err := a.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+
+	if err != nil {
+		t.Fatalf("Check %q : %v", service, err)
+	}
+
+	return res.Status
+}
+
+// waitForStatus waits for the bank service and the server to turn want.
+func waitForStatus(t *testing.T, a *GrpcAdapter, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for _, service := range []string{bank.BankService_ServiceDesc.ServiceName, ""} {
+		for servingStatus(t, a, service) != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("%q status = %v, want %v", service, servingStatus(t, a, service), want)
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestWatchBankHealth(t *testing.T) {
+	pinger := &fakePinger{results: make(chan error)}
+	a := NewGrpcAdapter(nil, nil, nil, config.GrpcConfig{HealthCheckInterval: time.Millisecond})
+	a.bankHealth = pinger
+	a.health = newHealthServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := make(chan struct{})
+
+	go func() {
+		defer close(watched)
+		a.watchBankHealth(ctx)
+	}()
+
+	defer func() {
+		cancel()
+		<-watched
+	}()
+
+	// unknown until the first check
+	waitForStatus(t, a, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	for _, step := range []struct {
+		name string
+		err  error
+		want healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"database up", nil, healthpb.HealthCheckResponse_SERVING},
+		{"database down", errors.New("database unreachable : connection refused"),
+			healthpb.HealthCheckResponse_NOT_SERVING},
+		{"still down", errors.New("database unreachable : connection refused"),
+			healthpb.HealthCheckResponse_NOT_SERVING},
+		{"recovered", nil, healthpb.HealthCheckResponse_SERVING},
+	} {
+		pinger.results <- step.err
+		waitForStatus(t, a, step.want)
+
+		if got := servingStatus(t, a, hello.HelloService_ServiceDesc.ServiceName); got !=
+			healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("%v : hello status = %v, want SERVING whatever the database", step.name, got)
+		}
+	}
+
+	// a check answered once the server is shutting down doesn't turn it SERVING again
+	a.health.Shutdown()
+	pinger.results <- nil
+	cancel()
+	<-watched
+
+	if got := servingStatus(t, a, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status after shutdown = %v, want NOT_SERVING", got)
+	}
+}
@@ -4,14 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"time"
 
 	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
 )
 
 func (a *GrpcAdapter) SayHello(ctx context.Context, req *hello.HelloRequest) (*hello.HelloResponse, error) {
-	greet := a.helloService.GenerateHello(req.Name)
+	greet := a.helloService.GenerateHello(ctx, req.Name)
 
 	return &hello.HelloResponse{
 		Greet: greet,
@@ -21,7 +20,7 @@ func (a *GrpcAdapter) SayHello(ctx context.Context, req *hello.HelloRequest) (*h
 func (a *GrpcAdapter) SayManyHellos(req *hello.HelloRequest,
 	stream hello.HelloService_SayManyHellosServer) error {
 	for i := 0; i < 10; i++ {
-		greet := a.helloService.GenerateHello(req.Name)
+		greet := a.helloService.GenerateHello(stream.Context(), req.Name)
 
 		res := fmt.Sprintf("[%d] %s", i, greet)
 
@@ -52,10 +51,10 @@ func (a *GrpcAdapter) SayHelloToEveryone(stream hello.HelloService_SayHelloToEve
 		}
 
 		if err != nil {
-			log.Fatalln("Error while reading from client :", err)
+			return streamError("SayHelloToEveryone", err)
 		}
 
-		greet := a.helloService.GenerateHello(req.Name)
+		greet := a.helloService.GenerateHello(stream.Context(), req.Name)
 
 		res += greet + " "
 	}
@@ -70,10 +69,10 @@ func (a *GrpcAdapter) SayHelloContinuous(stream hello.HelloService_SayHelloConti
 		}
 
 		if err != nil {
-			log.Fatalln("Error while reading from client :", err)
+			return streamError("SayHelloContinuous", err)
 		}
 
-		greet := a.helloService.GenerateHello(req.Name)
+		greet := a.helloService.GenerateHello(stream.Context(), req.Name)
 
 		err = stream.Send(
 			&hello.HelloResponse{
@@ -82,7 +81,7 @@ func (a *GrpcAdapter) SayHelloContinuous(stream hello.HelloService_SayHelloConti
 		)
 
 		if err != nil {
-			log.Fatalln("Error while sending response to client :", err)
+			return streamError("SayHelloContinuous", err)
 		}
 	}
 }
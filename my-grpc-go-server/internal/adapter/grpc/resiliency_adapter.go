@@ -30,8 +30,14 @@ func generateErrStatus(statusCode uint32) error {
 func (a *GrpcAdapter) UnaryResiliency(ctx context.Context, req *resl.ResiliencyRequest) (
 	*resl.ResiliencyResponse, error) {
 	log.Println("UnaryResiliency called")
-	str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+	portCtx, span := startPortSpan(ctx, "ResiliencyService.GenerateResiliency")
+	str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 		req.MaxDelaySecond, req.StatusCodes)
+	endPortSpan(span, err)
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
 
 	if errStatus := generateErrStatus(sts); errStatus != nil {
 		return nil, errStatus
@@ -53,9 +59,17 @@ func (a *GrpcAdapter) ServerStreamingResiliency(req *resl.ResiliencyRequest,
 		case <-context.Done():
 			log.Println("Client cancelled request")
 			return nil
+		case <-a.shutdown:
+			return errServerShuttingDown
 		default:
-			str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(context, "ResiliencyService.GenerateResiliency")
+			str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -86,8 +100,14 @@ func (a *GrpcAdapter) ClientStreamingResiliency(
 		}
 
 		if req != nil {
-			_, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(stream.Context(), "ResiliencyService.GenerateResiliency")
+			_, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -109,6 +129,8 @@ func (a *GrpcAdapter) BiDirectionalResiliency(
 		case <-context.Done():
 			log.Println("Client cancelled request")
 			return nil
+		case <-a.shutdown:
+			return errServerShuttingDown
 		default:
 			req, err := stream.Recv()
 
@@ -117,11 +139,17 @@ func (a *GrpcAdapter) BiDirectionalResiliency(
 			}
 
 			if err != nil {
-				log.Fatalln("Error while reading from client :", err)
+				return streamError("BiDirectionalResiliency", err)
 			}
 
-			str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(context, "ResiliencyService.GenerateResiliency")
+			str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -134,7 +162,7 @@ func (a *GrpcAdapter) BiDirectionalResiliency(
 			)
 
 			if err != nil {
-				log.Fatalln("Error while sending response to client :", err)
+				return streamError("BiDirectionalResiliency", err)
 			}
 		}
 	}
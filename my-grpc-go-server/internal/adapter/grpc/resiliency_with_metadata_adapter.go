@@ -12,6 +12,7 @@ import (
 	resl "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func dummyRequestMetadata(ctx context.Context) {
@@ -38,8 +39,14 @@ func dummyResponseMetadata() metadata.MD {
 func (a *GrpcAdapter) UnaryResiliencyWithMetadata(ctx context.Context, req *resl.ResiliencyRequest) (
 	*resl.ResiliencyResponse, error) {
 	log.Println("UnaryResiliencyWithMetadata called")
-	str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+	portCtx, span := startPortSpan(ctx, "ResiliencyService.GenerateResiliency")
+	str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 		req.MaxDelaySecond, req.StatusCodes)
+	endPortSpan(span, err)
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
 
 	// read request metadata
 	dummyRequestMetadata(ctx)
@@ -73,9 +80,17 @@ func (a *GrpcAdapter) ServerStreamingResiliencyWithMetadata(req *resl.Resiliency
 		case <-context.Done():
 			log.Println("Client cancelled request")
 			return nil
+		case <-a.shutdown:
+			return errServerShuttingDown
 		default:
-			str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(context, "ResiliencyService.GenerateResiliency")
+			str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -113,8 +128,14 @@ func (a *GrpcAdapter) ClientStreamingResiliencyWithMetadata(
 		dummyRequestMetadata(context)
 
 		if req != nil {
-			_, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(context, "ResiliencyService.GenerateResiliency")
+			_, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -140,6 +161,8 @@ func (a *GrpcAdapter) BiDirectionalResiliencyWithMetadata(
 		case <-context.Done():
 			log.Println("Client cancelled request")
 			return nil
+		case <-a.shutdown:
+			return errServerShuttingDown
 		default:
 			req, err := stream.Recv()
 
@@ -148,13 +171,19 @@ func (a *GrpcAdapter) BiDirectionalResiliencyWithMetadata(
 			}
 
 			if err != nil {
-				log.Fatalln("Error while reading from client :", err)
+				return streamError("BiDirectionalResiliencyWithMetadata", err)
 			}
 
 			dummyRequestMetadata(context)
 
-			str, sts := a.resiliencyService.GenerateResiliency(req.MinDelaySecond,
+			portCtx, span := startPortSpan(context, "ResiliencyService.GenerateResiliency")
+			str, sts, err := a.resiliencyService.GenerateResiliency(portCtx, req.MinDelaySecond,
 				req.MaxDelaySecond, req.StatusCodes)
+			endPortSpan(span, err)
+
+			if err != nil {
+				return status.FromContextError(err).Err()
+			}
 
 			if errStatus := generateErrStatus(sts); errStatus != nil {
 				return errStatus
@@ -167,7 +196,7 @@ func (a *GrpcAdapter) BiDirectionalResiliencyWithMetadata(
 			)
 
 			if err != nil {
-				log.Fatalln("Error while sending response to client :", err)
+				return streamError("BiDirectionalResiliencyWithMetadata", err)
 			}
 		}
 	}
@@ -1,23 +1,75 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"time"
 
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/interceptor"
 	"github.com/timpamungkas/my-grpc-go-server/internal/port"
 	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
 	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
 	resl "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
+	mytls "github.com/timpamungkas/my-grpc-tls"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
+// errServerShuttingDown ends the streams still open when the server shuts down, so clients
+// retry them on another instance.
+var errServerShuttingDown = status.Error(codes.Unavailable, "server is shutting down")
+
+// streamError logs err returned by Recv or Send on a stream of method, and turns it into the
+// status returned by the handler. A client cancelling the stream or running out of deadline is
+// not a server failure, it is only logged as such.
+func streamError(method string, err error) error {
+	st := status.Convert(err)
+
+	switch st.Code() {
+	case codes.Canceled, codes.DeadlineExceeded:
+		log.Printf("%v : client ended the stream (%v) : %v\n", method, st.Code(), st.Message())
+		return st.Err()
+	case codes.Unknown:
+		log.Printf("%v : stream failed : %v\n", method, err)
+		return status.Errorf(codes.Internal, "stream failed : %v", err)
+	default:
+		log.Printf("%v : stream failed (%v) : %v\n", method, st.Code(), st.Message())
+		return st.Err()
+	}
+}
+
+// canceledStatus returns the status of an RPC whose port call failed because ctx is done : the
+// client cancelled it or ran out of deadline, the port error is not the cause. It returns nil
+// while ctx is not done.
+func canceledStatus(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
+	return nil
+}
+
 type GrpcAdapter struct {
 	helloService      port.HelloServicePort
 	bankService       port.BankServicePort
 	resiliencyService port.ResiliencyServicePort
-	grpcPort          int
+	authenticator     port.AuthenticatorPort
+	bankHealth        port.HealthCheckPort
+	cfg               config.GrpcConfig
 	server            *grpc.Server
+	health            *health.Server
+	// shutdown is closed when the server starts shutting down, streaming handlers end their
+	// stream once the current message is done
+	shutdown chan struct{}
 	hello.HelloServiceServer
 	bank.BankServiceServer
 	resl.ResiliencyServiceServer
@@ -25,43 +77,80 @@ type GrpcAdapter struct {
 }
 
 func NewGrpcAdapter(helloService port.HelloServicePort, bankService port.BankServicePort,
-	resiliencyService port.ResiliencyServicePort, grpcPort int) *GrpcAdapter {
+	resiliencyService port.ResiliencyServicePort, cfg config.GrpcConfig) *GrpcAdapter {
 	return &GrpcAdapter{
 		helloService:      helloService,
 		bankService:       bankService,
 		resiliencyService: resiliencyService,
-		grpcPort:          grpcPort,
+		bankHealth:        bankService,
+		cfg:               cfg,
+		shutdown:          make(chan struct{}),
 	}
 }
 
-func (a *GrpcAdapter) Run() {
-	var err error
-
-	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", a.grpcPort))
+// Run serves gRPC until ctx is done, then shuts the server down gracefully : streaming handlers
+// are notified, in-flight RPCs get up to the configured shutdown timeout to finish, and the
+// remaining ones are cut.
+func (a *GrpcAdapter) Run(ctx context.Context) error {
+	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", a.cfg.Port))
 
 	if err != nil {
-		log.Fatalf("Failed to listen on port %d : %v\n", a.grpcPort, err)
+		return fmt.Errorf("failed to listen on port %d : %v", a.cfg.Port, err)
 	}
 
-	log.Printf("Server listening on port %d\n", a.grpcPort)
+	log.Printf("Server listening on port %d\n", a.cfg.Port)
 
-	// creds, err := credentials.NewServerTLSFromFile("ssl/server.crt", "ssl/server.pem")
+	return a.Serve(ctx, listen)
+}
 
-	// if err != nil {
-	// 	log.Fatalln("Can't create server credentials :", err)
-	// }
+// Serve is Run on an existing listener, which is closed when Serve returns.
+func (a *GrpcAdapter) Serve(ctx context.Context, listen net.Listener) error {
+	var opts []grpc.ServerOption
 
-	grpcServer := grpc.NewServer(
-	// grpc.Creds(creds),
-	// grpc.ChainUnaryInterceptor(
-	// 	interceptor.LogUnaryServerInterceptor(),
-	// 	interceptor.BasicUnaryServerInterceptor(),
-	// ),
-	// grpc.ChainStreamInterceptor(
-	// 	interceptor.LogStreamServerInterceptor(),
-	// 	interceptor.BasicStreamServerInterceptor(),
-	// ),
-	)
+	if a.cfg.Tls.Enabled {
+		tlsConfig, err := mytls.NewServerTLSConfig(a.cfg.Tls, "h2")
+
+		if err != nil {
+			listen.Close()
+			return fmt.Errorf("can't create server credentials : %v", err)
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	opts = append(opts, interceptor.ServerOptions(a.cfg)...)
+
+	if a.cfg.Auth.Enabled {
+		if a.authenticator == nil {
+			listen.Close()
+			return fmt.Errorf("grpc.auth is enabled but no authenticator is set")
+		}
+
+		// after the interceptors above, refused calls are logged with their request id
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(interceptor.AuthUnaryServerInterceptor(a.authenticator,
+				bank.BankService_ServiceDesc.ServiceName)),
+			grpc.ChainStreamInterceptor(interceptor.AuthStreamServerInterceptor(a.authenticator,
+				bank.BankService_ServiceDesc.ServiceName)),
+		)
+	}
+
+	if a.cfg.RateLimit.Enabled {
+		limiter, err := interceptor.NewRateLimiter(a.cfg.RateLimit)
+
+		if err != nil {
+			listen.Close()
+			return fmt.Errorf("can't create rate limiter : %v", err)
+		}
+
+		// after authentication, authenticated clients are limited by subject
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(interceptor.RateLimitUnaryServerInterceptor(limiter)),
+			grpc.ChainStreamInterceptor(interceptor.RateLimitStreamServerInterceptor(limiter)),
+		)
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 
 	a.server = grpcServer
 
@@ -70,11 +159,65 @@ func (a *GrpcAdapter) Run() {
 	resl.RegisterResiliencyServiceServer(grpcServer, a)
 	resl.RegisterResiliencyWithMetadataServiceServer(grpcServer, a)
 
-	if err = grpcServer.Serve(listen); err != nil {
-		log.Fatalf("Failed to serve gRPC on port %d : %v\n", a.grpcPort, err)
+	a.health = newHealthServer()
+	healthpb.RegisterHealthServer(grpcServer, a.health)
+
+	if a.cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	defer stopHealth()
+
+	go a.watchBankHealth(healthCtx)
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- grpcServer.Serve(listen)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve gRPC on %v : %v", listen.Addr(), err)
+	case <-ctx.Done():
+	}
+
+	a.gracefulStop()
+
+	if err := <-serveErr; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+
+	return nil
+}
+
+func (a *GrpcAdapter) gracefulStop() {
+	log.Printf("Server shutting down, draining RPCs for up to %v\n", a.cfg.ShutdownTimeout)
+
+	// load balancers stop routing new RPCs here while the current ones drain
+	a.health.Shutdown()
+	close(a.shutdown)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		a.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		log.Println("Server stopped gracefully")
+	case <-time.After(a.cfg.ShutdownTimeout):
+		log.Println("Shutdown timeout reached, cutting remaining RPCs")
+		a.server.Stop()
+		<-stopped
 	}
 }
 
+// Stop stops the server immediately, cutting in-flight RPCs. Cancel the context given to Run
+// for a graceful shutdown.
 func (a *GrpcAdapter) Stop() {
 	a.server.Stop()
 }
@@ -0,0 +1,411 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	resl "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakePorts answers BankService and ResiliencyService port calls. While block is set, the port
+// calls block until their context is done or release is closed, as a slow database would.
+type fakePorts struct {
+	port.BankServicePort
+
+	block atomic.Bool
+	// started receives the name of a blocking port call once it runs, canceled once its
+	// context is done
+	started  chan string
+	canceled chan string
+	release  chan struct{}
+	// exchangeRate is the rate of the transfers
+	exchangeRate decimal.Decimal
+	// created counts the CreateTransaction calls
+	created atomic.Int32
+	// balanceSpan is the span context given to the last FindCurrentBalance call
+	balanceSpan atomic.Value
+}
+
+func newFakePorts() *fakePorts {
+	return &fakePorts{
+		started:      make(chan string, 10),
+		canceled:     make(chan string, 10),
+		release:      make(chan struct{}),
+		exchangeRate: decimal.NewFromInt(1),
+	}
+}
+
+func (f *fakePorts) wait(ctx context.Context, name string) error {
+	if !f.block.Load() {
+		return nil
+	}
+
+	f.started <- name
+
+	select {
+	case <-ctx.Done():
+	case <-f.release:
+		return nil
+	}
+
+	f.canceled <- name
+
+	return ctx.Err()
+}
+
+func (f *fakePorts) CheckHealth(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakePorts) FindCurrentBalance(ctx context.Context, acct string) (decimal.Decimal, string, error) {
+	f.balanceSpan.Store(trace.SpanContextFromContext(ctx))
+
+	return decimal.NewFromInt(100), "USD", nil
+}
+
+func (f *fakePorts) FindAccountLocation(ctx context.Context, acct string) (*time.Location, error) {
+	return time.UTC, nil
+}
+
+func (f *fakePorts) CreateTransaction(ctx context.Context, acct string, t dbank.Transaction) (uuid.UUID, error) {
+	f.created.Add(1)
+
+	return uuid.New(), f.wait(ctx, "CreateTransaction")
+}
+
+func (f *fakePorts) CalculateTransactionSummary(ctx context.Context, tcur *dbank.TransactionSummary,
+	trans dbank.Transaction) error {
+	return nil
+}
+
+func (f *fakePorts) Transfer(ctx context.Context, tt dbank.TransferTransaction) (dbank.TransferResult, error) {
+	if err := f.wait(ctx, "Transfer"); err != nil {
+		return dbank.TransferResult{}, err
+	}
+
+	return dbank.TransferResult{TransferSuccess: true, TransferTimestamp: time.Now(),
+		ExchangeRate: f.exchangeRate}, nil
+}
+
+// SubscribeExchangeRates sends one rate, exchangeRate, from fromCur to toCur.
+func (f *fakePorts) SubscribeExchangeRates(ctx context.Context, fromCur string,
+	toCur string) (<-chan dbank.ExchangeRate, func(), error) {
+	rates := make(chan dbank.ExchangeRate, 1)
+	rates <- dbank.ExchangeRate{FromCurrency: fromCur, ToCurrency: toCur, Rate: f.exchangeRate,
+		ValidFromTimestamp: time.Now()}
+
+	return rates, func() {}, nil
+}
+
+func (f *fakePorts) GenerateResiliency(ctx context.Context, minDelaySecond int32, maxDelaySecond int32,
+	statusCodes []uint32) (string, uint32, error) {
+	return "resiliency", 0, f.wait(ctx, "GenerateResiliency")
+}
+
+// startTestServer serves the adapter on an in-memory listener until the test ends, and returns
+// a function dialing it. Connections are closed when the test ends.
+func startTestServer(t *testing.T, ports *fakePorts) func() *grpc.ClientConn {
+	t.Helper()
+
+	return startTestServerWith(t, ports, config.GrpcConfig{
+		ShutdownTimeout:     10 * time.Second,
+		HealthCheckInterval: time.Minute,
+		Interceptors:        true,
+	})
+}
+
+// startTestServerWith is startTestServer with cfg.
+func startTestServerWith(t *testing.T, ports *fakePorts, cfg config.GrpcConfig) func() *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	a := NewGrpcAdapter(nil, ports, ports, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+
+	go func() {
+		served <- a.Serve(ctx, lis)
+	}()
+
+	// runs after the connections dialed below are closed
+	t.Cleanup(func() {
+		cancel()
+
+		// a handler stuck on an aborted stream would hold the graceful stop up to its timeout
+		select {
+		case err := <-served:
+			if err != nil {
+				t.Errorf("Serve : %v", err)
+			}
+		case <-time.After(3 * time.Second):
+			t.Errorf("server did not stop, a handler is still running")
+		}
+	})
+
+	return func() *grpc.ClientConn {
+		return dialTestServer(t, lis)
+	}
+}
+
+// dialTestServer returns a connection to the server on lis, closed when the test ends.
+func dialTestServer(t *testing.T, lis *bufconn.Listener) *grpc.ClientConn {
+	t.Helper()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("Dial : %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+func usd(units int64) *money.Money {
+	return &money.Money{CurrencyCode: "USD", Units: units}
+}
+
+// waitFor waits for the port call name on ch.
+func waitFor(t *testing.T, ch chan string, name string) {
+	t.Helper()
+
+	select {
+	case got := <-ch:
+		if got != name {
+			t.Fatalf("port call = %v, want %v", got, name)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for %v", name)
+	}
+}
+
+func TestAbortedStreams(t *testing.T) {
+	tests := []struct {
+		name string
+		port string
+		// open opens the stream with ctx and sends its first message
+		open func(ctx context.Context, conn *grpc.ClientConn) error
+	}{
+		{
+			name: "SummarizeTransactions",
+			port: "CreateTransaction",
+			open: func(ctx context.Context, conn *grpc.ClientConn) error {
+				stream, err := bank.NewBankServiceClient(conn).SummarizeTransactions(ctx)
+
+				if err != nil {
+					return err
+				}
+
+				return stream.Send(&bank.Transaction{AccountNumber: "A1",
+					Type: bank.TransactionType_TRANSACTION_TYPE_IN, Amount: usd(10)})
+			},
+		},
+		{
+			name: "TransferMultiple",
+			port: "Transfer",
+			open: func(ctx context.Context, conn *grpc.ClientConn) error {
+				stream, err := bank.NewBankServiceClient(conn).TransferMultiple(ctx)
+
+				if err != nil {
+					return err
+				}
+
+				return stream.Send(&bank.TransferRequest{FromAccountNumber: "A1", ToAccountNumber: "A2",
+					Currency: "USD", Amount: usd(10)})
+			},
+		},
+		{
+			name: "BiDirectionalResiliencyWithMetadata",
+			port: "GenerateResiliency",
+			open: func(ctx context.Context, conn *grpc.ClientConn) error {
+				stream, err := resl.NewResiliencyWithMetadataServiceClient(conn).
+					BiDirectionalResiliencyWithMetadata(ctx)
+
+				if err != nil {
+					return err
+				}
+
+				return stream.Send(&resl.ResiliencyRequest{StatusCodes: []uint32{0}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, abort := range []string{"cancel", "close connection"} {
+			t.Run(tt.name+" "+abort, func(t *testing.T) {
+				ports := newFakePorts()
+				dial := startTestServer(t, ports)
+				conn, streamConn := dial(), dial()
+
+				ports.block.Store(true)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				if err := tt.open(ctx, streamConn); err != nil {
+					t.Fatalf("open stream : %v", err)
+				}
+
+				waitFor(t, ports.started, tt.port)
+
+				if abort == "cancel" {
+					cancel()
+				} else {
+					streamConn.Close()
+				}
+
+				// the port call mid-stream is cancelled with the stream
+				waitFor(t, ports.canceled, tt.port)
+				ports.block.Store(false)
+
+				assertServing(t, conn)
+			})
+		}
+	}
+}
+
+// assertServing checks the server still answers unary and streaming calls.
+func assertServing(t *testing.T, conn *grpc.ClientConn) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	res, err := bank.NewBankServiceClient(conn).GetCurrentBalance(ctx,
+		&bank.CurrentBalanceRequest{AccountNumber: "A1"})
+
+	if err != nil {
+		t.Fatalf("GetCurrentBalance after abort : %v", err)
+	}
+
+	if res.Amount.GetUnits() != 100 {
+		t.Errorf("GetCurrentBalance = %v, want 100 USD", res.Amount)
+	}
+
+	stream, err := resl.NewResiliencyWithMetadataServiceClient(conn).BiDirectionalResiliencyWithMetadata(ctx)
+
+	if err != nil {
+		t.Fatalf("BiDirectionalResiliencyWithMetadata after abort : %v", err)
+	}
+
+	if err := stream.Send(&resl.ResiliencyRequest{StatusCodes: []uint32{0}}); err != nil {
+		t.Fatalf("Send after abort : %v", err)
+	}
+
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv after abort : %v", err)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend after abort : %v", err)
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("Recv after CloseSend = %v, want EOF", err)
+	}
+}
+
+func TestGracefulStop(t *testing.T) {
+	tests := []struct {
+		name            string
+		shutdownTimeout time.Duration
+		// release lets the in-flight RPC finish once the shutdown started, else it only ends
+		// when cut
+		release  bool
+		wantCode codes.Code
+	}{
+		{"in-flight RPC drains", 10 * time.Second, true, codes.OK},
+		{"stopped after the shutdown timeout", 200 * time.Millisecond, false, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ports := newFakePorts()
+			ports.block.Store(true)
+			lis := bufconn.Listen(1 << 20)
+			a := NewGrpcAdapter(nil, ports, ports, config.GrpcConfig{
+				ShutdownTimeout:     tt.shutdownTimeout,
+				HealthCheckInterval: time.Minute,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			served := make(chan error, 1)
+
+			go func() {
+				served <- a.Serve(ctx, lis)
+			}()
+
+			conn := dialTestServer(t, lis)
+			rpcErr := make(chan error, 1)
+
+			go func() {
+				_, err := resl.NewResiliencyServiceClient(conn).UnaryResiliency(context.Background(),
+					&resl.ResiliencyRequest{})
+				rpcErr <- err
+			}()
+
+			waitFor(t, ports.started, "GenerateResiliency")
+			start := time.Now()
+			cancel()
+
+			// the server waits for the RPC
+			select {
+			case err := <-served:
+				t.Fatalf("Serve returned %v with an RPC in flight", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			if tt.release {
+				close(ports.release)
+			}
+
+			select {
+			case err := <-served:
+				if err != nil {
+					t.Errorf("Serve : %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("server did not stop")
+			}
+
+			if elapsed := time.Since(start); !tt.release && elapsed < tt.shutdownTimeout {
+				t.Errorf("server stopped after %v, want the %v shutdown timeout", elapsed, tt.shutdownTimeout)
+			}
+
+			if err := <-rpcErr; status.Code(err) != tt.wantCode {
+				t.Errorf("UnaryResiliency = %v, want %v", err, tt.wantCode)
+			}
+
+			if !tt.release {
+				waitFor(t, ports.canceled, "GenerateResiliency")
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/timpamungkas/my-grpc-go-server/internal/adapter/grpc")
+
+// startPortSpan starts the span of an application port call made while handling an RPC, as a
+// child of the RPC span. No span is started when the RPC is not traced.
+func startPortSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// endPortSpan ends span, marking it failed when the port call returned err.
+func endPortSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/bank"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	recorderOnce sync.Once
+	recorder     *tracetest.SpanRecorder
+)
+
+// spanRecorder returns the recorder of every span. It is installed once as the global tracer
+// provider, since the package tracers keep delegating to the first one.
+func spanRecorder() *tracetest.SpanRecorder {
+	recorderOnce.Do(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+
+	return recorder
+}
+
+// traceSpans returns the ended spans of the trace, by name.
+func traceSpans(r *tracetest.SpanRecorder, traceId trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+
+	for _, span := range r.Ended() {
+		if span.SpanContext().TraceID() == traceId {
+			spans[span.Name()] = span
+		}
+	}
+
+	return spans
+}
+
+// withRemoteParent returns a context sending parent to the server in the traceparent header,
+// as the gateway does.
+func withRemoteParent(parent trace.SpanContext) context.Context {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), parent), carrier)
+
+	return metadata.NewOutgoingContext(context.Background(), metadata.New(carrier))
+}
+
+func TestTracing(t *testing.T) {
+	r := spanRecorder()
+
+	tests := []struct {
+		name    string
+		tracing bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{0x01, byte(i + 1)},
+				SpanID:     trace.SpanID{0x02, byte(i + 1)},
+				TraceFlags: trace.FlagsSampled,
+				Remote:     true,
+			})
+			ports := newFakePorts()
+			conn := startTestServerWith(t, ports, config.GrpcConfig{
+				ShutdownTimeout:     10 * time.Second,
+				HealthCheckInterval: time.Minute,
+				Interceptors:        true,
+				Tracing:             tt.tracing,
+			})()
+			ended := len(r.Ended())
+
+			_, err := bank.NewBankServiceClient(conn).GetCurrentBalance(withRemoteParent(parent),
+				&bank.CurrentBalanceRequest{AccountNumber: "A1"})
+
+			if err != nil {
+				t.Fatalf("GetCurrentBalance : %v", err)
+			}
+
+			portSpan := ports.balanceSpan.Load().(trace.SpanContext)
+
+			if !tt.tracing {
+				if got := len(r.Ended()) - ended; got != 0 {
+					t.Errorf("%v spans ended, want none", got)
+				}
+
+				if portSpan.IsValid() {
+					t.Errorf("port call traced in %v, want no span", portSpan.TraceID())
+				}
+
+				return
+			}
+
+			spans := traceSpans(r, parent.TraceID())
+			rpc, port := spans["bank.BankService/GetCurrentBalance"], spans["BankService.FindCurrentBalance"]
+
+			if rpc == nil || port == nil {
+				t.Fatalf("spans of the trace = %v, want the RPC and port call spans", spans)
+			}
+
+			if rpc.SpanKind() != trace.SpanKindServer || rpc.Parent().SpanID() != parent.SpanID() {
+				t.Errorf("RPC span is a %v child of %v, want a server child of %v", rpc.SpanKind(),
+					rpc.Parent().SpanID(), parent.SpanID())
+			}
+
+			if port.Parent().SpanID() != rpc.SpanContext().SpanID() {
+				t.Errorf("port call span is a child of %v, want the RPC span %v", port.Parent().SpanID(),
+					rpc.SpanContext().SpanID())
+			}
+
+			// database queries of the port call are children of its span
+			if portSpan.SpanID() != port.SpanContext().SpanID() {
+				t.Errorf("port call context carries span %v, want %v", portSpan.SpanID(),
+					port.SpanContext().SpanID())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+func TestCreateAccountNumberTaken(t *testing.T) {
+	taken := fmt.Errorf("%w : 7800000000", db.ErrAccountNumberExists)
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{"first attempt", nil, nil, 1},
+		{"taken once", []error{taken}, nil, 2},
+		{"taken until the last attempt", []error{taken, taken}, nil, accountNumberMaxCreated},
+		{"always taken", []error{taken, taken, taken, taken}, dbank.ErrAccountNumberGeneration,
+			accountNumberMaxCreated},
+		{"other error", []error{errors.New("connection reset")}, dbank.ErrCreateAccountFailed, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeBankDatabase()
+			fake.createAccountErrs = tt.errs
+
+			_, acct, err := NewBankService(fake).CreateAccount(context.Background(), dbank.Account{
+				AccountName:          "Retry test",
+				Currency:             "USD",
+				InitialDepositAmount: decimal.NewFromInt(10),
+			})
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("CreateAccount = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(fake.createdAccounts) != tt.wantAttempts {
+				t.Fatalf("CreateAccount attempts = %v, want %v", len(fake.createdAccounts), tt.wantAttempts)
+			}
+
+			if tt.wantErr == nil && acct != fake.createdAccounts[len(fake.createdAccounts)-1] {
+				t.Errorf("account number = %v, want the last one tried %v", acct, fake.createdAccounts)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+)
+
+func TestAuthorizeAccount(t *testing.T) {
+	owner := "alice"
+	owned := testAccount("A1")
+	owned.OwnerSubject = &owner
+
+	s := NewBankService(newFakeBankDatabase(owned, testAccount("A2")))
+
+	tests := []struct {
+		name    string
+		p       dauth.Principal
+		acct    string
+		wantErr error
+	}{
+		{"owner", dauth.Principal{Subject: "alice", Method: dauth.MethodJwt}, "A1", nil},
+		{"other subject", dauth.Principal{Subject: "bob", Method: dauth.MethodJwt}, "A1", dauth.ErrPermissionDenied},
+		// a client certificate named alice is not the token subject alice
+		{"certificate named after the owner", dauth.Principal{Subject: "x509:alice", Method: dauth.MethodMtls},
+			"A1", dauth.ErrPermissionDenied},
+		{"account without owner", dauth.Principal{Subject: "alice", Method: dauth.MethodJwt}, "A2",
+			dauth.ErrPermissionDenied},
+		{"unknown account", dauth.Principal{Subject: "alice", Method: dauth.MethodJwt}, "A3",
+			dauth.ErrPermissionDenied},
+		{"admin", dauth.Principal{Subject: "x509:spiffe://bank/admin", Method: dauth.MethodMtls, Admin: true},
+			"A1", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.AuthorizeAccount(context.Background(), tt.p, tt.acct); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorizeAccount = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,39 +1,398 @@
 package application
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
-	"github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
 	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
 	"github.com/timpamungkas/my-grpc-go-server/internal/port"
 )
 
+const (
+	accountNumberPrefix       = "78"
+	accountNumberLength       = 10
+	accountNumberMaxGenerated = 10
+	// accountNumberMaxCreated bounds the inserts of an account whose number was taken meanwhile
+	accountNumberMaxCreated = 3
+
+	defaultTransactionPageSize = 50
+	maxTransactionPageSize     = 500
+)
+
+var currencyPattern = regexp.MustCompile("^[A-Z]{3}$")
+
 type BankService struct {
-	db port.BankDatabasePort
+	db              port.BankDatabasePort
+	exchangeRates   *ExchangeRateBroadcaster
+	valueDateWindow dbank.ValueDateWindow
+	// defaultLocation is the business day time zone of accounts without one
+	defaultLocation *time.Location
+	// migrationErr is set when the database schema could not be migrated at startup
+	migrationErr error
 }
 
 func NewBankService(dbPort port.BankDatabasePort) *BankService {
 	return &BankService{
-		db: dbPort,
+		db:              dbPort,
+		exchangeRates:   NewExchangeRateBroadcaster(),
+		valueDateWindow: dbank.DefaultValueDateWindow(),
+		defaultLocation: time.UTC,
+	}
+}
+
+// SetMigrationError records the database migration failure, the bank service then reports
+// itself unhealthy. Call it before serving.
+func (s *BankService) SetMigrationError(err error) {
+	s.migrationErr = err
+}
+
+// SetValueDateWindow limits how far back or forward new transactions can be value dated. Call it
+// before serving.
+func (s *BankService) SetValueDateWindow(w dbank.ValueDateWindow) {
+	s.valueDateWindow = w
+}
+
+// SetDefaultLocation sets the business day time zone of accounts created without one. Call it
+// before serving.
+func (s *BankService) SetDefaultLocation(loc *time.Location) {
+	s.defaultLocation = loc
+}
+
+// CheckHealth returns an error when bank requests can't be served : the database schema is not
+// migrated or the database is unreachable.
+func (s *BankService) CheckHealth(ctx context.Context) error {
+	if s.migrationErr != nil {
+		return fmt.Errorf("database migration failed : %v", s.migrationErr)
+	}
+
+	if err := s.db.Ping(ctx); err != nil {
+		return fmt.Errorf("database unreachable : %v", err)
 	}
+
+	return nil
 }
 
-func (s *BankService) FindCurrentBalance(acct string) (float64, error) {
-	bankAccount, err := s.db.GetBankAccountByAccountNumber(acct)
+func (s *BankService) FindCurrentBalance(ctx context.Context, acct string) (decimal.Decimal, string, error) {
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
 
 	if err != nil {
 		log.Println("Error on FindCurrentBalance :", err)
-		return 0, err
+		return decimal.Zero, "", err
+	}
+
+	return bankAccount.CurrentBalance, bankAccount.Currency, nil
+}
+
+// ChangeAccountStatus moves an account to status c.Status and records the change in the account
+// status history. Frozen accounts can only receive money, closed accounts can't transact and
+// must have a zero balance.
+func (s *BankService) ChangeAccountStatus(ctx context.Context,
+	c dbank.AccountStatusChange) (dbank.AccountStatusChangeResult, error) {
+	switch c.Status {
+	case dbank.AccountStatusActive, dbank.AccountStatusFrozen, dbank.AccountStatusClosed:
+	default:
+		return dbank.AccountStatusChangeResult{}, fmt.Errorf("%w : %q", dbank.ErrInvalidAccountStatus, c.Status)
 	}
 
-	return bankAccount.CurrentBalance, nil
+	if strings.TrimSpace(c.Reason) == "" {
+		return dbank.AccountStatusChangeResult{}, dbank.ErrAccountStatusReasonRequired
+	}
+
+	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, c.AccountNumber)
+
+	if err != nil {
+		log.Printf("Can't change status of account %v : %v\n", c.AccountNumber, err)
+		return dbank.AccountStatusChangeResult{}, fmt.Errorf("%w : %v", dbank.ErrAccountNotFound, c.AccountNumber)
+	}
+
+	history, err := s.db.UpdateAccountStatus(ctx, db.BankAccountStatusHistoryOrm{
+		HistoryUuid: uuid.New(),
+		AccountUuid: bankAccountOrm.AccountUuid,
+		NewStatus:   c.Status,
+		Reason:      c.Reason,
+		ChangedBy:   nullableString(c.ChangedBy),
+		ChangedAt:   time.Now(),
+	})
+
+	if err != nil {
+		log.Printf("Can't change status of account %v to %v : %v\n", c.AccountNumber, c.Status, err)
+		return dbank.AccountStatusChangeResult{}, err
+	}
+
+	log.Printf("Account %v status changed from %v to %v : %v\n", c.AccountNumber, history.PreviousStatus,
+		history.NewStatus, c.Reason)
+
+	return dbank.AccountStatusChangeResult{
+		PreviousStatus: history.PreviousStatus,
+		Status:         history.NewStatus,
+		ChangedAt:      history.ChangedAt,
+	}, nil
 }
 
-func (s *BankService) CreateExchangeRate(r dbank.ExchangeRate) (uuid.UUID, error) {
+// FindAccountLocation returns the time zone defining the business day of the account acct :
+// its own, or the default location.
+func (s *BankService) FindAccountLocation(ctx context.Context, acct string) (*time.Location, error) {
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
+
+	if err != nil {
+		log.Println("Error on FindAccountLocation :", err)
+		return nil, fmt.Errorf("%w : %v", dbank.ErrAccountNotFound, acct)
+	}
+
+	return s.accountLocation(bankAccount), nil
+}
+
+// accountLocation returns the time zone of the account a, or the default location.
+func (s *BankService) accountLocation(a db.BankAccountOrm) *time.Location {
+	if a.TimeZone == nil {
+		return s.defaultLocation
+	}
+
+	loc, err := dbank.LoadTimeZone(*a.TimeZone)
+
+	if err != nil {
+		log.Printf("Account %v has an unknown time zone, using %v : %v\n", a.AccountNumber,
+			s.defaultLocation, err)
+		return s.defaultLocation
+	}
+
+	return loc
+}
+
+// FindAccountPolicy returns the outgoing limits of the account acct, the zero policy when none
+// is stored.
+func (s *BankService) FindAccountPolicy(ctx context.Context, acct string) (dbank.AccountPolicy, error) {
+	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
+
+	if err != nil {
+		log.Println("Error on FindAccountPolicy :", err)
+		return dbank.AccountPolicy{}, fmt.Errorf("%w : %v", dbank.ErrAccountNotFound, acct)
+	}
+
+	policyOrm, _, err := s.db.GetAccountPolicy(ctx, bankAccountOrm.AccountUuid)
+
+	if err != nil {
+		log.Printf("Can't find policy of account %v : %v\n", acct, err)
+		return dbank.AccountPolicy{}, err
+	}
+
+	return toAccountPolicy(bankAccountOrm, policyOrm), nil
+}
+
+// UpdateAccountPolicy replaces the outgoing limits of the account p.AccountNumber. The limits
+// are in the account currency, p.Currency may be empty.
+func (s *BankService) UpdateAccountPolicy(ctx context.Context, p dbank.AccountPolicy) (dbank.AccountPolicy, error) {
+	if err := p.Validate(); err != nil {
+		return dbank.AccountPolicy{}, err
+	}
+
+	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, p.AccountNumber)
+
+	if err != nil {
+		log.Printf("Can't update policy of account %v : %v\n", p.AccountNumber, err)
+		return dbank.AccountPolicy{}, fmt.Errorf("%w : %v", dbank.ErrAccountNotFound, p.AccountNumber)
+	}
+
+	if p.Currency != "" && p.Currency != bankAccountOrm.Currency {
+		return dbank.AccountPolicy{}, fmt.Errorf("%w : policy in %v, account in %v", dbank.ErrCurrencyMismatch,
+			p.Currency, bankAccountOrm.Currency)
+	}
+
+	now := time.Now()
+
+	policyOrm, err := s.db.SaveAccountPolicy(ctx, db.BankAccountPolicyOrm{
+		AccountUuid:          bankAccountOrm.AccountUuid,
+		OverdraftLimit:       dbank.RoundAmount(p.OverdraftLimit),
+		MaxTransactionAmount: dbank.RoundAmount(p.MaxTransactionAmount),
+		DailyOutgoingLimit:   dbank.RoundAmount(p.DailyOutgoingLimit),
+		MonthlyOutgoingLimit: dbank.RoundAmount(p.MonthlyOutgoingLimit),
+		UpdatedBy:            nullableString(p.UpdatedBy),
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	})
+
+	if err != nil {
+		log.Printf("Can't update policy of account %v : %v\n", p.AccountNumber, err)
+		return dbank.AccountPolicy{}, err
+	}
+
+	log.Printf("Account %v policy updated : overdraft %v, max transaction %v, daily %v, monthly %v\n",
+		p.AccountNumber, policyOrm.OverdraftLimit, policyOrm.MaxTransactionAmount,
+		policyOrm.DailyOutgoingLimit, policyOrm.MonthlyOutgoingLimit)
+
+	return toAccountPolicy(bankAccountOrm, policyOrm), nil
+}
+
+func toAccountPolicy(a db.BankAccountOrm, p db.BankAccountPolicyOrm) dbank.AccountPolicy {
+	policy := dbank.AccountPolicy{
+		AccountNumber:        a.AccountNumber,
+		Currency:             a.Currency,
+		OverdraftLimit:       p.OverdraftLimit,
+		MaxTransactionAmount: p.MaxTransactionAmount,
+		DailyOutgoingLimit:   p.DailyOutgoingLimit,
+		MonthlyOutgoingLimit: p.MonthlyOutgoingLimit,
+		UpdatedAt:            p.UpdatedAt,
+	}
+
+	if p.UpdatedBy != nil {
+		policy.UpdatedBy = *p.UpdatedBy
+	}
+
+	return policy
+}
+
+// AuthorizeAccount returns dauth.ErrPermissionDenied unless p owns the account acct or is an
+// admin. An unknown account is denied too, so callers can't probe which accounts exist.
+func (s *BankService) AuthorizeAccount(ctx context.Context, p dauth.Principal, acct string) error {
+	if p.Admin {
+		return nil
+	}
+
+	bankAccount, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
+
+	if err != nil {
+		log.Println("Error on AuthorizeAccount :", err)
+		return dauth.ErrPermissionDenied
+	}
+
+	if bankAccount.OwnerSubject == nil || *bankAccount.OwnerSubject != p.Subject {
+		log.Printf("Access to account %v denied to %v\n", acct, p.Subject)
+		return dauth.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func ownerSubject(owner string) *string {
+	if owner == "" {
+		return nil
+	}
+
+	return &owner
+}
+
+func (s *BankService) CreateAccount(ctx context.Context, a dbank.Account) (uuid.UUID, string, error) {
+	if strings.TrimSpace(a.AccountName) == "" {
+		return uuid.Nil, "", dbank.ErrInvalidAccountName
+	}
+
+	if !currencyPattern.MatchString(a.Currency) {
+		return uuid.Nil, "", dbank.ErrInvalidCurrency
+	}
+
+	initialDeposit := dbank.RoundAmount(a.InitialDepositAmount)
+
+	if initialDeposit.IsNegative() {
+		return uuid.Nil, "", dbank.ErrInvalidInitialDeposit
+	}
+
+	if a.TimeZone != "" {
+		if _, err := dbank.LoadTimeZone(a.TimeZone); err != nil {
+			return uuid.Nil, "", err
+		}
+	}
+
+	now := time.Now()
+	newUuid := uuid.New()
+
+	bankAccountOrm := db.BankAccountOrm{
+		AccountUuid:    newUuid,
+		AccountName:    a.AccountName,
+		Currency:       a.Currency,
+		CurrentBalance: initialDeposit,
+		CreatedAt:      now,
+		OwnerSubject:   ownerSubject(a.Owner),
+		TimeZone:       nullableString(a.TimeZone),
+		Status:         dbank.AccountStatusActive,
+		UpdatedAt:      now,
+	}
+
+	var initialDepositOrm *db.BankTransactionOrm
+
+	if initialDeposit.IsPositive() {
+		initialDepositOrm = &db.BankTransactionOrm{
+			TransactionUuid:      uuid.New(),
+			AccountUuid:          newUuid,
+			TransactionTimestamp: now,
+			ValueTimestamp:       now,
+			Amount:               initialDeposit,
+			TransactionType:      dbank.TransactionTypeIn,
+			Notes:                "Initial deposit",
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		}
+	}
+
+	// a concurrent request can take the generated number before the insert
+	for i := 1; ; i++ {
+		acct, err := s.generateAccountNumber(ctx)
+
+		if err != nil {
+			log.Println("Can't generate account number :", err)
+			return uuid.Nil, "", dbank.ErrAccountNumberGeneration
+		}
+
+		bankAccountOrm.AccountNumber = acct
+		savedUuid, err := s.db.CreateAccount(ctx, bankAccountOrm, initialDepositOrm)
+
+		if errors.Is(err, db.ErrAccountNumberExists) {
+			log.Printf("Can't create account %v (attempt %v of %v) : %v\n", acct, i, accountNumberMaxCreated, err)
+
+			if i < accountNumberMaxCreated {
+				continue
+			}
+
+			return uuid.Nil, "", dbank.ErrAccountNumberGeneration
+		}
+
+		if err != nil {
+			log.Printf("Can't create account %v : %v\n", acct, err)
+			return uuid.Nil, "", dbank.ErrCreateAccountFailed
+		}
+
+		return savedUuid, acct, nil
+	}
+}
+
+func (s *BankService) generateAccountNumber(ctx context.Context) (string, error) {
+	for i := 0; i < accountNumberMaxGenerated; i++ {
+		acct := accountNumberPrefix
+
+		for len(acct) < accountNumberLength {
+			acct += fmt.Sprint(rand.Intn(10))
+		}
+
+		exist, err := s.db.IsAccountNumberExist(ctx, acct)
+
+		if err != nil {
+			return "", err
+		}
+
+		if !exist {
+			return acct, nil
+		}
+	}
+
+	return "", fmt.Errorf("no unique account number after %v attempts", accountNumberMaxGenerated)
+}
+
+func (s *BankService) CreateExchangeRate(ctx context.Context, r dbank.ExchangeRate) (uuid.UUID, error) {
 	newUuid := uuid.New()
 	now := time.Now()
 
@@ -48,96 +407,277 @@ func (s *BankService) CreateExchangeRate(r dbank.ExchangeRate) (uuid.UUID, error
 		UpdatedAt:          now,
 	}
 
-	return s.db.CreateExchangeRate(exchangeRateOrm)
+	if _, err := s.db.CreateExchangeRate(ctx, exchangeRateOrm); err != nil {
+		return uuid.Nil, err
+	}
+
+	s.exchangeRates.Publish(r)
+
+	return newUuid, nil
+}
+
+// SubscribeExchangeRates streams the exchange rates created for the currency pair, starting
+// with the last known rate. The returned function ends the subscription.
+func (s *BankService) SubscribeExchangeRates(ctx context.Context, fromCur string,
+	toCur string) (<-chan dbank.ExchangeRate, func(), error) {
+	if !currencyPattern.MatchString(fromCur) || !currencyPattern.MatchString(toCur) {
+		return nil, nil, dbank.ErrInvalidCurrency
+	}
+
+	// nothing published since start up, fall back to the rate stored in database
+	if _, found := s.exchangeRates.LastRate(fromCur, toCur); !found {
+		if exchangeRateOrm, err := s.db.GetExchangeRateAtTimestamp(ctx, fromCur, toCur, time.Now()); err == nil {
+			s.exchangeRates.Seed(dbank.ExchangeRate{
+				FromCurrency:       exchangeRateOrm.FromCurrency,
+				ToCurrency:         exchangeRateOrm.ToCurrency,
+				Rate:               exchangeRateOrm.Rate,
+				ValidFromTimestamp: exchangeRateOrm.ValidFromTimestamp,
+				ValidToTimestamp:   exchangeRateOrm.ValidToTimestamp,
+			})
+		}
+	}
+
+	rates, unsubscribe := s.exchangeRates.Subscribe(fromCur, toCur)
+
+	return rates, unsubscribe, nil
 }
 
-func (s *BankService) FindExchangeRate(fromCur string, toCur string, ts time.Time) (float64, error) {
-	exchangeRate, err := s.db.GetExchangeRateAtTimestamp(fromCur, toCur, ts)
+func (s *BankService) FindExchangeRate(ctx context.Context, fromCur string, toCur string,
+	ts time.Time) (decimal.Decimal, error) {
+	exchangeRate, err := s.db.GetExchangeRateAtTimestamp(ctx, fromCur, toCur, ts)
 
 	if err != nil {
-		return 0, err
+		return decimal.Zero, err
 	}
 
-	return float64(exchangeRate.Rate), nil
+	return exchangeRate.Rate, nil
 }
 
-func (s *BankService) CreateTransaction(acct string, t dbank.Transaction) (uuid.UUID, error) {
+func (s *BankService) CreateTransaction(ctx context.Context, acct string,
+	t dbank.Transaction) (uuid.UUID, error) {
 	newUuid := uuid.New()
 	now := time.Now()
 
-	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(acct)
+	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, acct)
 
 	if err != nil {
 		log.Printf("Can't create transaction for %v : %v\n", acct, err)
 		return uuid.Nil, fmt.Errorf("can't find account number %v : %v", acct, err.Error())
 	}
 
-	if t.TransactionType == bank.TransactionTypeOut && bankAccountOrm.CurrentBalance < t.Amount {
-		return bankAccountOrm.AccountUuid, fmt.Errorf(
-			"insufficient account balance %v for [out] transaction amount %v",
-			bankAccountOrm.CurrentBalance, t.Amount,
-		)
+	t.Amount = dbank.RoundAmount(t.Amount)
+
+	if !t.Amount.IsPositive() {
+		return bankAccountOrm.AccountUuid, dbank.ErrInvalidAmount
+	}
+
+	if t.Currency != "" && t.Currency != bankAccountOrm.Currency {
+		return bankAccountOrm.AccountUuid, fmt.Errorf("%w : transaction in %v, account in %v",
+			dbank.ErrCurrencyMismatch, t.Currency, bankAccountOrm.Currency)
 	}
 
 	transactionOrm := db.BankTransactionOrm{
 		TransactionUuid:      newUuid,
 		AccountUuid:          bankAccountOrm.AccountUuid,
 		TransactionTimestamp: now,
+		ValueTimestamp:       t.Timestamp,
 		Amount:               t.Amount,
 		TransactionType:      t.TransactionType,
 		Notes:                t.Notes,
+		IdempotencyKey:       nullableString(t.IdempotencyKey),
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}
 
-	savedUuid, err := s.db.CreateTransaction(bankAccountOrm, transactionOrm)
+	// a retry is replayed even once its value date left the window
+	if replayUuid, replayed, err := s.findTransactionReplay(ctx, transactionOrm); replayed || err != nil {
+		return replayUuid, err
+	}
+
+	if transactionOrm.ValueTimestamp.IsZero() {
+		transactionOrm.ValueTimestamp = now
+	} else if !s.valueDateWindow.Contains(now, transactionOrm.ValueTimestamp) {
+		return bankAccountOrm.AccountUuid, fmt.Errorf("%w : %v is not between %v and %v",
+			dbank.ErrValueDateOutOfRange, transactionOrm.ValueTimestamp.Format(time.RFC3339),
+			now.Add(-s.valueDateWindow.MaxPast).Format(time.RFC3339),
+			now.Add(s.valueDateWindow.MaxFuture).Format(time.RFC3339))
+	}
+
+	// the policy check for [out] transaction is done by the database port under row lock
+	savedUuid, err := s.db.CreateTransaction(ctx, bankAccountOrm, transactionOrm,
+		dbank.NewOutgoingPeriods(now, s.accountLocation(bankAccountOrm)))
+
+	if err != nil {
+		// a concurrent request with the same idempotency key may have won the insert
+		if replayUuid, replayed, replayErr := s.findTransactionReplay(ctx, transactionOrm); replayed ||
+			replayErr != nil {
+			return replayUuid, replayErr
+		}
 
-	return savedUuid, err
+		log.Printf("Can't create transaction for %v : %v\n", acct, err)
+		return bankAccountOrm.AccountUuid, err
+	}
+
+	return savedUuid, nil
 }
 
-func (s *BankService) CalculateTransactionSummary(tcur *dbank.TransactionSummary,
+// findTransactionReplay looks up a transaction already stored with the idempotency key of t.
+// It returns the stored transaction uuid and true when t is a replay of it, or
+// ErrIdempotencyKeyReused when the key was used for a different transaction.
+func (s *BankService) findTransactionReplay(ctx context.Context,
+	t db.BankTransactionOrm) (uuid.UUID, bool, error) {
+	if t.IdempotencyKey == nil {
+		return uuid.Nil, false, nil
+	}
+
+	existing, found, err := s.db.GetTransactionByIdempotencyKey(ctx, *t.IdempotencyKey)
+
+	if err != nil || !found {
+		return uuid.Nil, false, err
+	}
+
+	if existing.AccountUuid != t.AccountUuid || existing.TransactionType != t.TransactionType ||
+		!existing.Amount.Equal(t.Amount) {
+		return t.AccountUuid, false, fmt.Errorf("%w : %v", dbank.ErrIdempotencyKeyReused, *t.IdempotencyKey)
+	}
+
+	log.Printf("Replayed transaction %v for idempotency key %v\n", existing.TransactionUuid,
+		*t.IdempotencyKey)
+
+	return existing.TransactionUuid, true, nil
+}
+
+func nullableString(str string) *string {
+	if str == "" {
+		return nil
+	}
+
+	return &str
+}
+
+func (s *BankService) CalculateTransactionSummary(ctx context.Context, tcur *dbank.TransactionSummary,
 	trans dbank.Transaction) error {
-	switch trans.TransactionType {
-	case dbank.TransactionTypeIn:
-		tcur.SumIn += trans.Amount
-	case dbank.TransactionTypeOut:
-		tcur.SumOut += trans.Amount
-	default:
-		return fmt.Errorf("unknown transaction type %v", trans.TransactionType)
+	if trans.TransactionType != dbank.TransactionTypeIn && trans.TransactionType != dbank.TransactionTypeOut {
+		return fmt.Errorf("%w : %v", dbank.ErrInvalidTransactionType, trans.TransactionType)
+	}
+
+	amount := dbank.RoundAmount(trans.Amount)
+	valueTimestamp := trans.Timestamp
+
+	if valueTimestamp.IsZero() {
+		valueTimestamp = time.Now()
 	}
 
-	tcur.SumTotal = tcur.SumIn - tcur.SumOut
+	// transactions are summarized by value date, not by the date they are booked. The value date
+	// is the date of the timestamp in its location, the caller converts it to the account location
+	valueDate := dbank.StartOfDay(valueTimestamp.Year(), valueTimestamp.Month(), valueTimestamp.Day(),
+		valueTimestamp.Location())
+	dsum := dateSummary(tcur, valueDate)
+
+	if trans.TransactionType == dbank.TransactionTypeIn {
+		tcur.SumIn = tcur.SumIn.Add(amount)
+		dsum.SumIn = dsum.SumIn.Add(amount)
+	} else {
+		tcur.SumOut = tcur.SumOut.Add(amount)
+		dsum.SumOut = dsum.SumOut.Add(amount)
+	}
+
+	tcur.SumTotal = tcur.SumIn.Sub(tcur.SumOut)
+	dsum.SumTotal = dsum.SumIn.Sub(dsum.SumOut)
+	tcur.SummaryOnDate = tcur.DateSummaries[len(tcur.DateSummaries)-1].ValueDate
 
 	return nil
 }
 
-func (s *BankService) Transfer(tt dbank.TransferTransaction) (uuid.UUID, bool, error) {
+// dateSummary returns the summary of valueDate in tcur, adding it in date order when missing.
+func dateSummary(tcur *dbank.TransactionSummary, valueDate time.Time) *dbank.TransactionDateSummary {
+	i := sort.Search(len(tcur.DateSummaries), func(i int) bool {
+		return !tcur.DateSummaries[i].ValueDate.Before(valueDate)
+	})
+
+	if i == len(tcur.DateSummaries) || !tcur.DateSummaries[i].ValueDate.Equal(valueDate) {
+		tcur.DateSummaries = append(tcur.DateSummaries, dbank.TransactionDateSummary{})
+		copy(tcur.DateSummaries[i+1:], tcur.DateSummaries[i:])
+		tcur.DateSummaries[i] = dbank.TransactionDateSummary{
+			ValueDate: valueDate,
+			SumIn:     decimal.Zero,
+			SumOut:    decimal.Zero,
+			SumTotal:  decimal.Zero,
+		}
+	}
+
+	return &tcur.DateSummaries[i]
+}
+
+func (s *BankService) Transfer(ctx context.Context, tt dbank.TransferTransaction) (dbank.TransferResult, error) {
 	now := time.Now()
 
-	fromAccountOrm, err := s.db.GetBankAccountByAccountNumber(tt.FromAccountNumber)
+	tt.Amount = dbank.RoundAmount(tt.Amount)
+
+	if !tt.Amount.IsPositive() {
+		return dbank.TransferResult{}, dbank.ErrInvalidAmount
+	}
+
+	// the transaction pair would lock the same account row twice
+	if tt.FromAccountNumber == tt.ToAccountNumber {
+		return dbank.TransferResult{}, fmt.Errorf("%w : %v", dbank.ErrTransferSameAccount, tt.FromAccountNumber)
+	}
+
+	fromAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, tt.FromAccountNumber)
 
 	if err != nil {
 		log.Printf("Can't find transfer from account %v : %v\n", tt.FromAccountNumber, err)
-		return uuid.Nil, false, dbank.ErrTransferSourceAccountNotFound
+		return dbank.TransferResult{}, dbank.ErrTransferSourceAccountNotFound
+	}
+
+	toAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, tt.ToAccountNumber)
+
+	if err != nil {
+		log.Printf("Can't find transfer to account %v : %v\n", tt.ToAccountNumber, err)
+		return dbank.TransferResult{}, dbank.ErrTransferDestinationAccountNotFound
+	}
+
+	if tt.Currency == "" {
+		tt.Currency = fromAccountOrm.Currency
+	}
+
+	// create transfer request
+	newTransferUuid := uuid.New()
+
+	transferOrm := db.BankTransferOrm{
+		TransferUuid:      newTransferUuid,
+		FromAccountUuid:   fromAccountOrm.AccountUuid,
+		ToAccountUuid:     toAccountOrm.AccountUuid,
+		Currency:          tt.Currency,
+		Amount:            tt.Amount,
+		TransferTimestamp: now,
+		TransferSuccess:   false,
+		IdempotencyKey:    nullableString(tt.IdempotencyKey),
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
-	if fromAccountOrm.CurrentBalance < tt.Amount {
-		return uuid.Nil, false, dbank.ErrTransferTransactionPair
+	// a retry is replayed with its stored rate, even once the current rate can't be found
+	if replay, replayed, err := s.findTransferReplay(ctx, transferOrm); replayed || err != nil {
+		return replay, err
 	}
 
-	toAccountOrm, err := s.db.GetBankAccountByAccountNumber(tt.ToAccountNumber)
+	debitAmount, creditAmount, exchangeRateOrm, err := s.convertTransferAmount(ctx, tt, fromAccountOrm,
+		toAccountOrm, now)
 
 	if err != nil {
-		log.Printf("Can't find transfer to account %v : %v\n", tt.ToAccountNumber, err)
-		return uuid.Nil, false, dbank.ErrTransferDestinationAccountNotFound
+		return dbank.TransferResult{}, err
 	}
 
+	transferOrm.ExchangeRate = exchangeRateOrm.Rate
+
 	fromTransactionOrm := db.BankTransactionOrm{
 		TransactionUuid:      uuid.New(),
 		TransactionTimestamp: now,
+		ValueTimestamp:       now,
 		TransactionType:      dbank.TransactionTypeOut,
 		AccountUuid:          fromAccountOrm.AccountUuid,
-		Amount:               tt.Amount,
+		Amount:               debitAmount,
 		Notes:                "Transfer out to " + tt.ToAccountNumber,
 		CreatedAt:            now,
 		UpdatedAt:            now,
@@ -146,39 +686,381 @@ func (s *BankService) Transfer(tt dbank.TransferTransaction) (uuid.UUID, bool, e
 	toTransactionOrm := db.BankTransactionOrm{
 		TransactionUuid:      uuid.New(),
 		TransactionTimestamp: now,
+		ValueTimestamp:       now,
 		TransactionType:      dbank.TransactionTypeIn,
 		AccountUuid:          toAccountOrm.AccountUuid,
-		Amount:               tt.Amount,
+		Amount:               creditAmount,
 		Notes:                "Transfer in from " + tt.FromAccountNumber,
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}
 
-	// create transfer request
-	newTransferUuid := uuid.New()
+	if exchangeRateOrm.ExchangeRateUuid != uuid.Nil {
+		transferOrm.ExchangeRateUuid = &exchangeRateOrm.ExchangeRateUuid
+	}
 
-	transferOrm := db.BankTransferOrm{
+	res := dbank.TransferResult{
 		TransferUuid:      newTransferUuid,
-		FromAccountUuid:   fromAccountOrm.AccountUuid,
-		ToAccountUuid:     toAccountOrm.AccountUuid,
-		Currency:          tt.Currency,
-		Amount:            tt.Amount,
 		TransferTimestamp: now,
-		TransferSuccess:   false,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+		ExchangeRate:      exchangeRateOrm.Rate,
+	}
+
+	// transfer record, transaction pair and transfer status are committed together, the policy
+	// check on source account is done by the database port under row lock
+	periods := dbank.NewOutgoingPeriods(now, s.accountLocation(fromAccountOrm))
+
+	err = s.db.RunInTransaction(ctx, func(uow db.BankUnitOfWork) error {
+		if _, err := uow.CreateTransfer(ctx, transferOrm); err != nil {
+			return fmt.Errorf("%w : %v", dbank.ErrTransferRecordFailed, err)
+		}
+
+		if _, err := uow.CreateTransferTransactionPair(ctx, fromAccountOrm, toAccountOrm,
+			fromTransactionOrm, toTransactionOrm, periods); err != nil {
+			return fmt.Errorf("%w : %w", dbank.ErrTransferTransactionPair, err)
+		}
+
+		if err := uow.UpdateTransferStatus(ctx, transferOrm, true); err != nil {
+			return fmt.Errorf("%w : %v", dbank.ErrTransferRecordFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		// a concurrent request with the same idempotency key may have won the insert
+		if replay, replayed, replayErr := s.findTransferReplay(ctx, transferOrm); replayed || replayErr != nil {
+			return replay, replayErr
+		}
+
+		log.Printf("Can't transfer from %v to %v : %v\n", tt.FromAccountNumber, tt.ToAccountNumber, err)
+
+		// rolled back because the caller gave up, there is no failure to record
+		if ctx.Err() != nil {
+			return dbank.TransferResult{}, ctx.Err()
+		}
+
+		failure := newTransferFailure(err)
+		s.recordFailedTransfer(ctx, transferOrm, err, failure)
+
+		if failure.Kind == transferFailureRecord {
+			return dbank.TransferResult{}, failure.Err()
+		}
+
+		return res, failure.Err()
 	}
 
-	if _, err := s.db.CreateTransfer(transferOrm); err != nil {
-		log.Printf("Can't create transfer from %v to %v : %v\n", tt.FromAccountNumber, tt.ToAccountNumber, err)
-		return uuid.Nil, false, dbank.ErrTransferRecordFailed
+	res.TransferSuccess = true
+
+	return res, nil
+}
+
+// convertTransferAmount returns the amount debited from the source account and credited to the
+// destination account for tt, together with the exchange rate applied between them. tt.Amount
+// is in tt.Currency, which must be the currency of either account. Same currency transfers use
+// rate 1 without looking up the stored exchange rates.
+func (s *BankService) convertTransferAmount(ctx context.Context, tt dbank.TransferTransaction,
+	fromAccountOrm db.BankAccountOrm, toAccountOrm db.BankAccountOrm,
+	ts time.Time) (decimal.Decimal, decimal.Decimal, db.BankExchangeRateOrm, error) {
+	if tt.Currency != fromAccountOrm.Currency && tt.Currency != toAccountOrm.Currency {
+		return decimal.Zero, decimal.Zero, db.BankExchangeRateOrm{}, fmt.Errorf(
+			"%w : transfer in %v from %v account to %v account", dbank.ErrCurrencyMismatch,
+			tt.Currency, fromAccountOrm.Currency, toAccountOrm.Currency)
 	}
 
-	if transferPairSuccess, _ := s.db.CreateTransferTransactionPair(fromAccountOrm,
-		toAccountOrm, fromTransactionOrm, toTransactionOrm); transferPairSuccess {
-		s.db.UpdateTransferStatus(transferOrm, true)
-		return newTransferUuid, true, nil
+	if fromAccountOrm.Currency == toAccountOrm.Currency {
+		return tt.Amount, tt.Amount, db.BankExchangeRateOrm{Rate: decimal.NewFromInt(1)}, nil
+	}
+
+	exchangeRateOrm, err := s.db.GetExchangeRateAtTimestamp(ctx, fromAccountOrm.Currency,
+		toAccountOrm.Currency, ts)
+
+	if err != nil || !exchangeRateOrm.Rate.IsPositive() {
+		log.Printf("Can't find exchange rate from %v to %v at %v : %v\n", fromAccountOrm.Currency,
+			toAccountOrm.Currency, ts, err)
+		return decimal.Zero, decimal.Zero, db.BankExchangeRateOrm{}, fmt.Errorf("%w from %v to %v at %v",
+			dbank.ErrExchangeRateNotFound, fromAccountOrm.Currency, toAccountOrm.Currency, ts.Format(time.RFC3339))
+	}
+
+	debitAmount, creditAmount := tt.Amount, tt.Amount
+
+	if tt.Currency == fromAccountOrm.Currency {
+		creditAmount = dbank.RoundAmount(tt.Amount.Mul(exchangeRateOrm.Rate))
 	} else {
-		return newTransferUuid, false, dbank.ErrTransferTransactionPair
+		debitAmount = dbank.RoundAmount(tt.Amount.Div(exchangeRateOrm.Rate))
+	}
+
+	// amount too small to be represented in the other currency
+	if !debitAmount.IsPositive() || !creditAmount.IsPositive() {
+		return decimal.Zero, decimal.Zero, db.BankExchangeRateOrm{}, fmt.Errorf("%w : %v %v converts to zero",
+			dbank.ErrInvalidAmount, tt.Amount, tt.Currency)
+	}
+
+	return debitAmount, creditAmount, exchangeRateOrm, nil
+}
+
+// recordFailedTransfer stores the rolled back transfer t with its failure reason, so failed
+// transfers can still be audited.
+func (s *BankService) recordFailedTransfer(ctx context.Context, t db.BankTransferOrm, cause error,
+	failure transferFailure) {
+	reason := cause.Error()
+
+	t.TransferSuccess = false
+	t.FailureReason = &reason
+	t.FailureKind = &failure.Kind
+
+	if failure.AccountStatus != nil || failure.AccountLimit != nil {
+		if detail, err := json.Marshal(failure); err == nil {
+			t.FailureDetail = nullableString(string(detail))
+		} else {
+			log.Printf("Can't encode failure of transfer %v : %v\n", t.TransferUuid, err)
+		}
+	}
+
+	if _, err := s.db.CreateTransfer(ctx, t); err != nil {
+		log.Printf("Can't record failed transfer %v : %v\n", t.TransferUuid, err)
+	}
+}
+
+// findTransferReplay looks up a transfer already stored with the idempotency key of t.
+// It returns the stored transfer result and true when t is a replay of it, or
+// ErrIdempotencyKeyReused when the key was used for a different transfer.
+func (s *BankService) findTransferReplay(ctx context.Context,
+	t db.BankTransferOrm) (dbank.TransferResult, bool, error) {
+	if t.IdempotencyKey == nil {
+		return dbank.TransferResult{}, false, nil
+	}
+
+	existing, found, err := s.db.GetTransferByIdempotencyKey(ctx, *t.IdempotencyKey)
+
+	if err != nil || !found {
+		return dbank.TransferResult{}, false, err
 	}
+
+	if existing.FromAccountUuid != t.FromAccountUuid || existing.ToAccountUuid != t.ToAccountUuid ||
+		existing.Currency != t.Currency || !existing.Amount.Equal(t.Amount) {
+		return dbank.TransferResult{}, false, fmt.Errorf("%w : %v",
+			dbank.ErrIdempotencyKeyReused, *t.IdempotencyKey)
+	}
+
+	log.Printf("Replayed transfer %v for idempotency key %v\n", existing.TransferUuid, *t.IdempotencyKey)
+
+	res := dbank.TransferResult{
+		TransferUuid:      existing.TransferUuid,
+		TransferSuccess:   existing.TransferSuccess,
+		TransferTimestamp: existing.TransferTimestamp,
+		ExchangeRate:      existing.ExchangeRate,
+		Replayed:          true,
+	}
+
+	// replay the original outcome, including the failure
+	if !existing.TransferSuccess {
+		return res, true, storedTransferFailure(existing).Err()
+	}
+
+	return res, true, nil
+}
+
+const (
+	transferFailureAccountStatus   = "ACCOUNT_STATUS"
+	transferFailureAccountLimit    = "ACCOUNT_LIMIT"
+	transferFailureTransactionPair = "TRANSACTION_PAIR"
+	transferFailureRecord          = "RECORD_FAILED"
+)
+
+// transferFailure is the cause of a failed transfer, stored with it so a replay of the transfer
+// returns the error it failed with.
+type transferFailure struct {
+	Kind          string                    `json:"kind"`
+	AccountStatus *dbank.AccountStatusError `json:"account_status,omitempty"`
+	AccountLimit  *dbank.AccountLimitError  `json:"account_limit,omitempty"`
+}
+
+func newTransferFailure(err error) transferFailure {
+	var statusErr *dbank.AccountStatusError
+	var limitErr *dbank.AccountLimitError
+
+	switch {
+	case errors.As(err, &statusErr):
+		return transferFailure{Kind: transferFailureAccountStatus, AccountStatus: statusErr}
+	case errors.As(err, &limitErr):
+		return transferFailure{Kind: transferFailureAccountLimit, AccountLimit: limitErr}
+	case errors.Is(err, dbank.ErrTransferTransactionPair):
+		return transferFailure{Kind: transferFailureTransactionPair}
+	default:
+		return transferFailure{Kind: transferFailureRecord}
+	}
+}
+
+// storedTransferFailure returns the failure stored with t. Transfers failed before failures were
+// stored have no kind, they failed on their transaction pair.
+func storedTransferFailure(t db.BankTransferOrm) transferFailure {
+	failure := transferFailure{Kind: transferFailureTransactionPair}
+
+	if t.FailureDetail != nil {
+		if err := json.Unmarshal([]byte(*t.FailureDetail), &failure); err != nil {
+			log.Printf("Can't decode failure of transfer %v : %v\n", t.TransferUuid, err)
+		}
+	}
+
+	if t.FailureKind != nil {
+		failure.Kind = *t.FailureKind
+	}
+
+	return failure
+}
+
+// Err returns the error of the failed transfer.
+func (f transferFailure) Err() error {
+	switch {
+	case f.Kind == transferFailureAccountStatus && f.AccountStatus != nil:
+		return f.AccountStatus
+	case f.Kind == transferFailureAccountLimit && f.AccountLimit != nil:
+		return f.AccountLimit
+	case f.Kind == transferFailureRecord:
+		return dbank.ErrTransferRecordFailed
+	default:
+		return dbank.ErrTransferTransactionPair
+	}
+}
+
+func (s *BankService) ListTransactions(ctx context.Context,
+	q dbank.TransactionQuery) (dbank.TransactionPage, error) {
+	bankAccountOrm, err := s.db.GetBankAccountByAccountNumber(ctx, q.AccountNumber)
+
+	if err != nil {
+		log.Printf("Can't list transactions for %v : %v\n", q.AccountNumber, err)
+		return dbank.TransactionPage{}, fmt.Errorf("%w : %v", dbank.ErrAccountNotFound, q.AccountNumber)
+	}
+
+	if !q.FromDate.IsZero() && !q.ToDate.IsZero() && q.ToDate.Before(q.FromDate) {
+		return dbank.TransactionPage{}, dbank.ErrInvalidDateRange
+	}
+
+	pageSize := q.PageSize
+
+	if pageSize <= 0 {
+		pageSize = defaultTransactionPageSize
+	} else if pageSize > maxTransactionPageSize {
+		pageSize = maxTransactionPageSize
+	}
+
+	// one extra row tells whether there is a next page
+	filter := db.BankTransactionFilter{
+		AccountUuid:     bankAccountOrm.AccountUuid,
+		FromTimestamp:   q.FromDate,
+		TransactionType: q.TransactionType,
+		Limit:           pageSize + 1,
+	}
+
+	if !q.ToDate.IsZero() {
+		y, m, d := q.ToDate.Date()
+		filter.ToTimestamp = dbank.StartOfDay(y, m, d+1, q.ToDate.Location())
+	}
+
+	if q.PageToken != "" {
+		cursor, err := decodeTransactionPageToken(q.PageToken, filter)
+
+		if err != nil {
+			return dbank.TransactionPage{}, fmt.Errorf("%w : %v", dbank.ErrInvalidPageToken, err)
+		}
+
+		filter.After = &cursor
+	}
+
+	transactionOrms, err := s.db.ListTransactions(ctx, filter)
+
+	if err != nil {
+		log.Printf("Can't list transactions for %v : %v\n", q.AccountNumber, err)
+		return dbank.TransactionPage{}, err
+	}
+
+	res := dbank.TransactionPage{}
+
+	if len(transactionOrms) > pageSize {
+		transactionOrms = transactionOrms[:pageSize]
+		last := transactionOrms[pageSize-1]
+
+		res.NextPageToken = encodeTransactionPageToken(db.BankTransactionCursor{
+			ValueTimestamp:  last.ValueTimestamp,
+			TransactionUuid: last.TransactionUuid,
+		}, filter)
+	}
+
+	res.Transactions = make([]dbank.Transaction, 0, len(transactionOrms))
+
+	for _, t := range transactionOrms {
+		trans := dbank.Transaction{
+			Amount:           t.Amount,
+			Currency:         bankAccountOrm.Currency,
+			Timestamp:        t.ValueTimestamp,
+			TransactionType:  t.TransactionType,
+			Notes:            t.Notes,
+			BookingTimestamp: t.TransactionTimestamp,
+		}
+
+		if t.IdempotencyKey != nil {
+			trans.IdempotencyKey = *t.IdempotencyKey
+		}
+
+		res.Transactions = append(res.Transactions, trans)
+	}
+
+	return res, nil
+}
+
+// encodeTransactionPageToken turns cursor into an opaque page token, only valid for the next
+// page of filter.
+func encodeTransactionPageToken(cursor db.BankTransactionCursor, filter db.BankTransactionFilter) string {
+	raw := strconv.FormatInt(cursor.ValueTimestamp.UnixNano(), 10) + "_" + cursor.TransactionUuid.String() +
+		"_" + transactionFilterHash(filter)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTransactionPageToken returns the cursor of token, refusing a token issued for another
+// filter than filter : its cursor would skip or repeat transactions.
+func decodeTransactionPageToken(token string, filter db.BankTransactionFilter) (db.BankTransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return db.BankTransactionCursor{}, err
+	}
+
+	parts := strings.Split(string(raw), "_")
+
+	if len(parts) != 3 {
+		return db.BankTransactionCursor{}, fmt.Errorf("malformed token %v", token)
+	}
+
+	nanos, uuidStr := parts[0], parts[1]
+
+	if parts[2] != transactionFilterHash(filter) {
+		return db.BankTransactionCursor{}, fmt.Errorf("token %v was issued for other filters", token)
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+
+	if err != nil {
+		return db.BankTransactionCursor{}, err
+	}
+
+	transactionUuid, err := uuid.Parse(uuidStr)
+
+	if err != nil {
+		return db.BankTransactionCursor{}, err
+	}
+
+	return db.BankTransactionCursor{
+		ValueTimestamp:  time.Unix(0, unixNano),
+		TransactionUuid: transactionUuid,
+	}, nil
+}
+
+// transactionFilterHash identifies the account, value date range and type of filter.
+func transactionFilterHash(filter db.BankTransactionFilter) string {
+	sum := sha256.Sum256([]byte(filter.AccountUuid.String() + "_" +
+		filter.FromTimestamp.UTC().Format(time.RFC3339Nano) + "_" +
+		filter.ToTimestamp.UTC().Format(time.RFC3339Nano) + "_" + filter.TransactionType))
+
+	return hex.EncodeToString(sum[:8])
 }
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	MethodJwt  = "jwt"
+	MethodMtls = "mtls"
+)
+
+// CertificateSubjectPrefix starts the subject of every client certificate principal, e.g.
+// x509:spiffe://bank/client or x509:alice. Token subjects can't start with it, so a certificate
+// never shares a subject, and the accounts it owns, with a token.
+const CertificateSubjectPrefix = "x509:"
+
+// Principal is the authenticated caller of an RPC : the subject of its bearer token, or the
+// identity of its client certificate prefixed with CertificateSubjectPrefix.
+type Principal struct {
+	Subject string
+	Method  string
+	// Admin principals may access every account
+	Admin bool
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal put in ctx by the authentication interceptor.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+
+	return p, ok
+}
+
+var ErrUnauthenticated = errors.New("missing bearer token or client certificate")
+var ErrInvalidCredentials = errors.New("invalid credentials")
+var ErrPermissionDenied = errors.New("caller doesn't own the account")
@@ -2,7 +2,11 @@ package bank
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -11,35 +15,385 @@ const (
 	TransactionTypeOut     string = "OUT"
 )
 
+const (
+	AccountStatusActive string = "ACTIVE"
+	// AccountStatusFrozen accounts can receive money but not send it
+	AccountStatusFrozen string = "FROZEN"
+	// AccountStatusClosed accounts can neither receive nor send money, and can't be reopened
+	AccountStatusClosed string = "CLOSED"
+)
+
+// AmountScale is the number of decimal places kept for every money amount,
+// matching the NUMERIC(15,2) columns in the database.
+const AmountScale int32 = 2
+
+// RoundAmount rounds d to AmountScale, half away from zero, the same way
+// PostgreSQL rounds values stored into NUMERIC(15,2).
+func RoundAmount(d decimal.Decimal) decimal.Decimal {
+	return d.Round(AmountScale)
+}
+
+type Account struct {
+	AccountName          string
+	Currency             string
+	InitialDepositAmount decimal.Decimal
+	// Owner is the subject of the caller creating the account, empty without authentication
+	Owner string
+	// TimeZone is the IANA time zone defining the account business day, empty for the bank default
+	TimeZone string
+}
+
+// LoadTimeZone returns the location of the IANA time zone name, e.g. Asia/Jakarta or UTC.
+// "Local" is refused as it depends on the host running the server.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w : %q", ErrInvalidTimeZone, name)
+	}
+
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidTimeZone, err)
+	}
+
+	return loc, nil
+}
+
+// AccountStatusChange sets the status of an account, Reason and ChangedBy are kept in the
+// account status history.
+type AccountStatusChange struct {
+	AccountNumber string
+	Status        string
+	Reason        string
+	// ChangedBy is the subject of the caller, empty without authentication
+	ChangedBy string
+}
+
+type AccountStatusChangeResult struct {
+	PreviousStatus string
+	Status         string
+	ChangedAt      time.Time
+}
+
+// CanChangeAccountStatus tells whether an account can go from status from to status to. A closed
+// account stays closed.
+func CanChangeAccountStatus(from string, to string) bool {
+	switch from {
+	case AccountStatusActive:
+		return to == AccountStatusFrozen || to == AccountStatusClosed
+	case AccountStatusFrozen:
+		return to == AccountStatusActive || to == AccountStatusClosed
+	default:
+		return false
+	}
+}
+
+// CheckAccountStatusChange returns an error wrapping ErrAccountStatusTransition when an account
+// can't go from status from to status to, or ErrAccountBalanceNotZero when it is closed with a
+// non-zero balance.
+func CheckAccountStatusChange(from string, to string, balance decimal.Decimal) error {
+	if !CanChangeAccountStatus(from, to) {
+		return fmt.Errorf("%w from %v to %v", ErrAccountStatusTransition, from, to)
+	}
+
+	if to == AccountStatusClosed && !balance.IsZero() {
+		return fmt.Errorf("%w : current balance %v", ErrAccountBalanceNotZero, balance)
+	}
+
+	return nil
+}
+
+// AccountStatusError tells that the status of an account forbids a transaction, it wraps
+// ErrAccountFrozen or ErrAccountClosed.
+type AccountStatusError struct {
+	AccountNumber string
+	Status        string
+}
+
+func (e *AccountStatusError) Error() string {
+	return fmt.Sprintf("%v : %v", e.Unwrap(), e.AccountNumber)
+}
+
+func (e *AccountStatusError) Unwrap() error {
+	if e.Status == AccountStatusFrozen {
+		return ErrAccountFrozen
+	}
+
+	return ErrAccountClosed
+}
+
+// CheckDebit returns an *AccountStatusError unless an account in status can send money.
+func CheckDebit(acct string, status string) error {
+	if status == AccountStatusActive {
+		return nil
+	}
+
+	return &AccountStatusError{AccountNumber: acct, Status: status}
+}
+
+// CheckCredit returns an *AccountStatusError unless an account in status can receive money.
+func CheckCredit(acct string, status string) error {
+	if status == AccountStatusActive || status == AccountStatusFrozen {
+		return nil
+	}
+
+	return &AccountStatusError{AccountNumber: acct, Status: status}
+}
+
+const (
+	LimitOverdraft       string = "OVERDRAFT"
+	LimitMaxTransaction  string = "MAX_TRANSACTION_AMOUNT"
+	LimitDailyOutgoing   string = "DAILY_OUTGOING"
+	LimitMonthlyOutgoing string = "MONTHLY_OUTGOING"
+)
+
+// AccountPolicy limits the money an account can send, amounts are in the account currency. A zero
+// limit is unlimited, except OverdraftLimit : a zero overdraft keeps the balance from going
+// below zero. Accounts without a stored policy have the zero policy.
+type AccountPolicy struct {
+	AccountNumber        string
+	Currency             string
+	OverdraftLimit       decimal.Decimal
+	MaxTransactionAmount decimal.Decimal
+	DailyOutgoingLimit   decimal.Decimal
+	MonthlyOutgoingLimit decimal.Decimal
+	// UpdatedBy is the subject of the caller, empty without authentication
+	UpdatedBy string
+	// UpdatedAt is zero for the default policy
+	UpdatedAt time.Time
+}
+
+// Validate returns ErrInvalidAccountPolicy when a limit of p is negative.
+func (p AccountPolicy) Validate() error {
+	limits := []struct {
+		name   string
+		amount decimal.Decimal
+	}{
+		{LimitOverdraft, p.OverdraftLimit},
+		{LimitMaxTransaction, p.MaxTransactionAmount},
+		{LimitDailyOutgoing, p.DailyOutgoingLimit},
+		{LimitMonthlyOutgoing, p.MonthlyOutgoingLimit},
+	}
+
+	for _, limit := range limits {
+		if limit.amount.IsNegative() {
+			return fmt.Errorf("%w : %v is %v", ErrInvalidAccountPolicy, limit.name, limit.amount)
+		}
+	}
+
+	return nil
+}
+
+// HasOutgoingLimits tells whether p limits the daily or monthly total sent, which needs
+// OutgoingTotals to be checked.
+func (p AccountPolicy) HasOutgoingLimits() bool {
+	return p.DailyOutgoingLimit.IsPositive() || p.MonthlyOutgoingLimit.IsPositive()
+}
+
+// OutgoingPeriods are the starts of the business day and month of an account, the outgoing
+// totals are summed from them.
+type OutgoingPeriods struct {
+	DayStart   time.Time
+	MonthStart time.Time
+}
+
+// NewOutgoingPeriods returns the business day and month containing now in loc.
+func NewOutgoingPeriods(now time.Time, loc *time.Location) OutgoingPeriods {
+	y, m, d := now.In(loc).Date()
+
+	return OutgoingPeriods{
+		DayStart:   StartOfDay(y, m, d, loc),
+		MonthStart: StartOfDay(y, m, 1, loc),
+	}
+}
+
+// OutgoingTotals are the amounts an account already sent in its current business day and month.
+type OutgoingTotals struct {
+	Day   decimal.Decimal
+	Month decimal.Decimal
+}
+
+// CheckOutgoing returns an *AccountLimitError when sending amount from account acct, with
+// balance and having already sent totals, breaches p.
+func (p AccountPolicy) CheckOutgoing(acct string, balance decimal.Decimal, amount decimal.Decimal,
+	totals OutgoingTotals) error {
+	if p.MaxTransactionAmount.IsPositive() && amount.GreaterThan(p.MaxTransactionAmount) {
+		return &AccountLimitError{AccountNumber: acct, Limit: LimitMaxTransaction,
+			Allowed: p.MaxTransactionAmount, Used: decimal.Zero, Requested: amount}
+	}
+
+	if p.DailyOutgoingLimit.IsPositive() && totals.Day.Add(amount).GreaterThan(p.DailyOutgoingLimit) {
+		return &AccountLimitError{AccountNumber: acct, Limit: LimitDailyOutgoing,
+			Allowed: p.DailyOutgoingLimit, Used: totals.Day, Requested: amount}
+	}
+
+	if p.MonthlyOutgoingLimit.IsPositive() && totals.Month.Add(amount).GreaterThan(p.MonthlyOutgoingLimit) {
+		return &AccountLimitError{AccountNumber: acct, Limit: LimitMonthlyOutgoing,
+			Allowed: p.MonthlyOutgoingLimit, Used: totals.Month, Requested: amount}
+	}
+
+	if balance.Add(p.OverdraftLimit).LessThan(amount) {
+		return &AccountLimitError{AccountNumber: acct, Limit: LimitOverdraft,
+			Allowed: p.OverdraftLimit, Used: decimal.Max(balance.Neg(), decimal.Zero), Requested: amount}
+	}
+
+	return nil
+}
+
+// AccountLimitError tells that an outgoing amount breaches the limit Limit of an account
+// policy. An overdraft breach wraps ErrInsufficientBalance, a single transaction breach
+// ErrTransactionLimitExceeded, and a daily or monthly breach ErrOutgoingLimitExceeded.
+type AccountLimitError struct {
+	AccountNumber string
+	Limit         string
+	Allowed       decimal.Decimal
+	// Used is the part of Allowed already used : the overdraft or the total sent in the period
+	Used      decimal.Decimal
+	Requested decimal.Decimal
+}
+
+func (e *AccountLimitError) Error() string {
+	return fmt.Sprintf("%v : account %v, %v limit %v, used %v, requested %v", e.Unwrap(),
+		e.AccountNumber, e.Limit, e.Allowed, e.Used, e.Requested)
+}
+
+func (e *AccountLimitError) Unwrap() error {
+	switch e.Limit {
+	case LimitOverdraft:
+		return ErrInsufficientBalance
+	case LimitMaxTransaction:
+		return ErrTransactionLimitExceeded
+	default:
+		return ErrOutgoingLimitExceeded
+	}
+}
+
 type ExchangeRate struct {
 	FromCurrency       string
 	ToCurrency         string
-	Rate               float64
+	Rate               decimal.Decimal
 	ValidFromTimestamp time.Time
 	ValidToTimestamp   time.Time
 }
 
+// ExchangeRatePair is a currency pair refreshed from an exchange rate provider every
+// RefreshInterval. Each fetched rate is valid for ValidFor.
+type ExchangeRatePair struct {
+	FromCurrency    string
+	ToCurrency      string
+	RefreshInterval time.Duration
+	ValidFor        time.Duration
+}
+
 type Transaction struct {
-	Amount          float64
-	Timestamp       time.Time
+	Amount   decimal.Decimal
+	Currency string
+	// Timestamp is the value date of the transaction, the current time when zero
+	Timestamp        time.Time
+	TransactionType  string
+	Notes            string
+	IdempotencyKey   string
+	BookingTimestamp time.Time
+}
+
+// ValueDateWindow limits how far the value date of a new transaction can be from the time it
+// is booked.
+type ValueDateWindow struct {
+	MaxPast   time.Duration
+	MaxFuture time.Duration
+}
+
+func DefaultValueDateWindow() ValueDateWindow {
+	return ValueDateWindow{
+		MaxPast:   30 * 24 * time.Hour,
+		MaxFuture: 24 * time.Hour,
+	}
+}
+
+// Contains tells whether a transaction booked at now can have the value date valueDate.
+func (w ValueDateWindow) Contains(now time.Time, valueDate time.Time) bool {
+	return !valueDate.Before(now.Add(-w.MaxPast)) && !valueDate.After(now.Add(w.MaxFuture))
+}
+
+// TransactionQuery selects a page of account transactions by value date. The dates are the start
+// of days in the account time zone, zero dates leave the range open. An empty TransactionType
+// matches every type.
+type TransactionQuery struct {
+	AccountNumber   string
+	FromDate        time.Time // inclusive
+	ToDate          time.Time // inclusive
 	TransactionType string
-	Notes           string
+	PageSize        int
+	PageToken       string
 }
 
+type TransactionPage struct {
+	Transactions  []Transaction
+	NextPageToken string
+}
+
+// TransactionSummary sums transactions, in total and by value date. SummaryOnDate is the latest
+// value date summarized.
 type TransactionSummary struct {
 	SummaryOnDate time.Time
-	SumIn         float64
-	SumOut        float64
-	SumTotal      float64
+	SumIn         decimal.Decimal
+	SumOut        decimal.Decimal
+	SumTotal      decimal.Decimal
+	// DateSummaries has one entry per value date, oldest first
+	DateSummaries []TransactionDateSummary
+}
+
+type TransactionDateSummary struct {
+	ValueDate time.Time
+	SumIn     decimal.Decimal
+	SumOut    decimal.Decimal
+	SumTotal  decimal.Decimal
 }
 
 type TransferTransaction struct {
 	FromAccountNumber string
 	ToAccountNumber   string
 	Currency          string
-	Amount            float64
+	Amount            decimal.Decimal
+	IdempotencyKey    string
+}
+
+type TransferResult struct {
+	TransferUuid      uuid.UUID
+	TransferSuccess   bool
+	TransferTimestamp time.Time
+	ExchangeRate      decimal.Decimal
+	Replayed          bool
 }
 
+var ErrInvalidAccountName = errors.New("account name can't be empty")
+var ErrInvalidCurrency = errors.New("currency must be a 3-letter ISO 4217 code")
+var ErrInvalidInitialDeposit = errors.New("initial deposit amount can't be negative")
+var ErrInvalidTimeZone = errors.New("time zone must be an IANA time zone name")
+var ErrAccountNumberGeneration = errors.New("can't generate unique account number")
+var ErrCreateAccountFailed = errors.New("can't create account record")
+var ErrAccountNotFound = errors.New("account not found")
+var ErrAccountFrozen = errors.New("account is frozen, it can only receive money")
+var ErrAccountClosed = errors.New("account is closed")
+var ErrInvalidAccountStatus = errors.New("account status must be ACTIVE, FROZEN or CLOSED")
+var ErrAccountStatusReasonRequired = errors.New("account status change reason can't be empty")
+var ErrAccountStatusTransition = errors.New("account status can't change")
+var ErrAccountBalanceNotZero = errors.New("account balance must be zero to close the account")
+
+var ErrInvalidAmount = errors.New("amount must be greater than zero")
+var ErrInvalidTransactionType = errors.New("transaction type must be IN or OUT")
+var ErrCurrencyMismatch = errors.New("currency doesn't match account currency")
+var ErrInsufficientBalance = errors.New("insufficient account balance")
+var ErrTransactionLimitExceeded = errors.New("amount exceeds the account maximum transaction amount")
+var ErrOutgoingLimitExceeded = errors.New("amount exceeds the account outgoing limit")
+var ErrInvalidAccountPolicy = errors.New("account policy limits can't be negative")
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used for a different request")
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+var ErrInvalidDateRange = errors.New("to date can't be before from date")
+var ErrInvalidPageToken = errors.New("invalid page token")
+var ErrValueDateOutOfRange = errors.New("transaction value date is outside the allowed window")
+
+var ErrTransferSameAccount = errors.New("source and destination account must differ")
 var ErrTransferSourceAccountNotFound = errors.New("source account not found")
 var ErrTransferDestinationAccountNotFound = errors.New("destination account not found")
 var ErrTransferRecordFailed = errors.New("can't create transfer record")
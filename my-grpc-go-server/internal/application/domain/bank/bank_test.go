@@ -0,0 +1,255 @@
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestCheckOutgoing(t *testing.T) {
+	limited := AccountPolicy{
+		OverdraftLimit:       dec("50"),
+		MaxTransactionAmount: dec("100"),
+		DailyOutgoingLimit:   dec("500"),
+		MonthlyOutgoingLimit: dec("2000"),
+	}
+
+	tests := []struct {
+		name    string
+		policy  AccountPolicy
+		balance string
+		amount  string
+		day     string
+		month   string
+		// wantLimit is the breached limit, empty when the amount can be sent
+		wantLimit string
+		wantUsed  string
+	}{
+		{"zero policy whole balance", AccountPolicy{}, "100", "100", "0", "0", "", ""},
+		{"zero policy one cent over balance", AccountPolicy{}, "100", "100.01", "0", "0", LimitOverdraft, "0"},
+		{"zero policy unlimited totals", AccountPolicy{}, "1000000", "900000", "5000000", "9000000", "", ""},
+		{"zero policy empty balance", AccountPolicy{}, "0", "0.01", "0", "0", LimitOverdraft, "0"},
+
+		{"max transaction exactly", limited, "1000", "100", "0", "0", "", ""},
+		{"max transaction one cent over", limited, "1000", "100.01", "0", "0", LimitMaxTransaction, "0"},
+
+		{"daily limit exactly", limited, "1000", "100", "400", "400", "", ""},
+		{"daily limit one cent over", limited, "1000", "100", "400.01", "400.01", LimitDailyOutgoing, "400.01"},
+
+		{"monthly limit exactly", limited, "1000", "100", "0", "1900", "", ""},
+		{"monthly limit one cent over", limited, "1000", "100", "0", "1900.01", LimitMonthlyOutgoing, "1900.01"},
+
+		{"overdraft floor exactly", limited, "50", "100", "0", "0", "", ""},
+		{"overdraft floor one cent over", limited, "49.99", "100", "0", "0", LimitOverdraft, "0"},
+		{"overdrawn down to the floor", limited, "-20", "30", "0", "0", "", ""},
+		{"overdrawn one cent below the floor", limited, "-20", "30.01", "0", "0", LimitOverdraft, "20"},
+
+		// the first breached limit is reported, in the order of CheckOutgoing
+		{"max transaction before totals", limited, "0", "100.01", "500", "2000", LimitMaxTransaction, "0"},
+		{"daily before monthly", limited, "0", "100", "500", "2000", LimitDailyOutgoing, "500"},
+		{"monthly before overdraft", limited, "0", "100", "0", "2000", LimitMonthlyOutgoing, "2000"},
+
+		{"unset daily limit", AccountPolicy{MonthlyOutgoingLimit: dec("2000")}, "1000", "100", "99999", "0", "", ""},
+		{"unset monthly limit", AccountPolicy{DailyOutgoingLimit: dec("500")}, "1000", "100", "0", "99999", "", ""},
+		{"unset max transaction", AccountPolicy{OverdraftLimit: dec("1000000")}, "0", "1000000", "0", "0", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.CheckOutgoing("A1", dec(tt.balance), dec(tt.amount),
+				OutgoingTotals{Day: dec(tt.day), Month: dec(tt.month)})
+
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("CheckOutgoing = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var limitErr *AccountLimitError
+
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("CheckOutgoing = %v, want an *AccountLimitError", err)
+			}
+
+			if limitErr.Limit != tt.wantLimit || !limitErr.Used.Equal(dec(tt.wantUsed)) ||
+				!limitErr.Requested.Equal(dec(tt.amount)) || limitErr.AccountNumber != "A1" {
+				t.Errorf("CheckOutgoing = %+v, want limit %v used %v requested %v", limitErr, tt.wantLimit,
+					tt.wantUsed, tt.amount)
+			}
+		})
+	}
+}
+
+func TestAccountLimitErrorUnwrap(t *testing.T) {
+	tests := []struct {
+		limit string
+		want  error
+	}{
+		{LimitOverdraft, ErrInsufficientBalance},
+		{LimitMaxTransaction, ErrTransactionLimitExceeded},
+		{LimitDailyOutgoing, ErrOutgoingLimitExceeded},
+		{LimitMonthlyOutgoing, ErrOutgoingLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		if err := error(&AccountLimitError{Limit: tt.limit}); !errors.Is(err, tt.want) {
+			t.Errorf("%v limit error = %v, want %v", tt.limit, err, tt.want)
+		}
+	}
+}
+
+func TestHasOutgoingLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy AccountPolicy
+		want   bool
+	}{
+		{"zero policy", AccountPolicy{}, false},
+		{"overdraft and max transaction only", AccountPolicy{OverdraftLimit: dec("10"),
+			MaxTransactionAmount: dec("10")}, false},
+		{"daily", AccountPolicy{DailyOutgoingLimit: dec("0.01")}, true},
+		{"monthly", AccountPolicy{MonthlyOutgoingLimit: dec("0.01")}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.HasOutgoingLimits(); got != tt.want {
+			t.Errorf("%v : HasOutgoingLimits = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+var accountStatuses = []string{AccountStatusActive, AccountStatusFrozen, AccountStatusClosed}
+
+func TestCanChangeAccountStatus(t *testing.T) {
+	// allowed[from][to], a closed account is never reopened
+	allowed := map[string]map[string]bool{
+		AccountStatusActive: {AccountStatusFrozen: true, AccountStatusClosed: true},
+		AccountStatusFrozen: {AccountStatusActive: true, AccountStatusClosed: true},
+		AccountStatusClosed: {},
+	}
+
+	for _, from := range append(accountStatuses, "", "UNKNOWN") {
+		for _, to := range append(accountStatuses, "", "UNKNOWN") {
+			if got := CanChangeAccountStatus(from, to); got != allowed[from][to] {
+				t.Errorf("CanChangeAccountStatus(%q, %q) = %v, want %v", from, to, got, allowed[from][to])
+			}
+		}
+	}
+}
+
+func TestCheckAccountStatusChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		balance string
+		wantErr error
+	}{
+		{"freeze", AccountStatusActive, AccountStatusFrozen, "100", nil},
+		{"unfreeze", AccountStatusFrozen, AccountStatusActive, "100", nil},
+		{"close active with zero balance", AccountStatusActive, AccountStatusClosed, "0", nil},
+		{"close frozen with zero balance", AccountStatusFrozen, AccountStatusClosed, "0", nil},
+		{"close with a positive balance", AccountStatusActive, AccountStatusClosed, "0.01",
+			ErrAccountBalanceNotZero},
+		{"close with a negative balance", AccountStatusFrozen, AccountStatusClosed, "-0.01",
+			ErrAccountBalanceNotZero},
+		{"reopen", AccountStatusClosed, AccountStatusActive, "0", ErrAccountStatusTransition},
+		{"freeze closed", AccountStatusClosed, AccountStatusFrozen, "0", ErrAccountStatusTransition},
+		{"close closed", AccountStatusClosed, AccountStatusClosed, "0", ErrAccountStatusTransition},
+		{"same status", AccountStatusActive, AccountStatusActive, "0", ErrAccountStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAccountStatusChange(tt.from, tt.to, dec(tt.balance))
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("CheckAccountStatusChange = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDebitAndCredit(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantDebit  error
+		wantCredit error
+	}{
+		{AccountStatusActive, nil, nil},
+		{AccountStatusFrozen, ErrAccountFrozen, nil},
+		{AccountStatusClosed, ErrAccountClosed, ErrAccountClosed},
+		// a status the account can't have is refused
+		{"UNKNOWN", ErrAccountClosed, ErrAccountClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			checks := []struct {
+				name  string
+				check func(acct string, status string) error
+				want  error
+			}{
+				{"CheckDebit", CheckDebit, tt.wantDebit},
+				{"CheckCredit", CheckCredit, tt.wantCredit},
+			}
+
+			for _, c := range checks {
+				err := c.check("A1", tt.status)
+
+				if !errors.Is(err, c.want) || (err != nil) != (c.want != nil) {
+					t.Errorf("%v = %v, want %v", c.name, err, c.want)
+				}
+
+				var statusErr *AccountStatusError
+
+				if err != nil && (!errors.As(err, &statusErr) || statusErr.AccountNumber != "A1" ||
+					statusErr.Status != tt.status) {
+					t.Errorf("%v = %#v, want an *AccountStatusError of A1 in %v", c.name, err, tt.status)
+				}
+			}
+		})
+	}
+}
+
+func TestValueDateWindowContains(t *testing.T) {
+	now := time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)
+	window := DefaultValueDateWindow()
+
+	tests := []struct {
+		name      string
+		window    ValueDateWindow
+		valueDate time.Time
+		want      bool
+	}{
+		{"now", window, now, true},
+		{"oldest", window, now.Add(-30 * 24 * time.Hour), true},
+		{"one nanosecond too old", window, now.Add(-30*24*time.Hour - time.Nanosecond), false},
+		{"latest", window, now.Add(24 * time.Hour), true},
+		{"one nanosecond too late", window, now.Add(24*time.Hour + time.Nanosecond), false},
+		// the edges are instants, not days
+		{"start of the oldest day", window, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), false},
+		{"end of the latest day", window, time.Date(2024, time.April, 1, 23, 59, 59, 0, time.UTC), false},
+		// an instant in another zone is compared as is
+		{"latest in another zone", window, now.Add(24 * time.Hour).In(time.FixedZone("UTC+14", 14*3600)), true},
+		{"zero window now", ValueDateWindow{}, now, true},
+		{"zero window one nanosecond before", ValueDateWindow{}, now.Add(-time.Nanosecond), false},
+		{"zero window one nanosecond after", ValueDateWindow{}, now.Add(time.Nanosecond), false},
+		{"past only", ValueDateWindow{MaxPast: time.Hour}, now.Add(-time.Hour), true},
+		{"past only future", ValueDateWindow{MaxPast: time.Hour}, now.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.window.Contains(now, tt.valueDate); got != tt.want {
+			t.Errorf("%v : Contains(%v) = %v, want %v", tt.name, tt.valueDate, got, tt.want)
+		}
+	}
+}
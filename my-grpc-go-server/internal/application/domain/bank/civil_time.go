@@ -0,0 +1,49 @@
+package bank
+
+import "time"
+
+// CivilTime is time.Date with a defined result around clock changes : a repeated local time is
+// its first occurrence, a skipped local time is moved forward by the length of the gap. Left to
+// itself, time.Date doesn't tell which of two instants it returns and moves skipped times back.
+func CivilTime(year int, month time.Month, day int, hour int, min int, sec int, nsec int,
+	loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, min, sec, nsec, loc)
+	requested := time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+
+	if gap := requested.Sub(wallClock(t)); gap > 0 {
+		return t.Add(gap)
+	}
+
+	return firstOccurrence(t)
+}
+
+// wallClock returns the wall clock of t as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		time.UTC)
+}
+
+// firstOccurrence returns the earliest instant showing the same wall clock as t in its location.
+func firstOccurrence(t time.Time) time.Time {
+	_, offset := t.Zone()
+	// the offset of a zone changes at most once a day
+	_, earlierOffset := t.Add(-24 * time.Hour).Zone()
+
+	if earlierOffset <= offset {
+		return t
+	}
+
+	earlier := t.Add(time.Duration(offset-earlierOffset) * time.Second)
+
+	if wallClock(earlier).Equal(wallClock(t)) {
+		return earlier
+	}
+
+	return t
+}
+
+// StartOfDay returns the first instant of a day in loc. A day whose midnight is skipped by a
+// clock change starts after the gap.
+func StartOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return CivilTime(year, month, day, 0, 0, 0, 0, loc)
+}
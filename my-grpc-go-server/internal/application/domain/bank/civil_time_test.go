@@ -0,0 +1,139 @@
+package bank
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		t.Fatalf("LoadLocation(%v) : %v", name, err)
+	}
+
+	return loc
+}
+
+func utc(year int, month time.Month, day int, hour int, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestCivilTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		zone  string
+		local [5]int // year, month, day, hour, minute
+		want  time.Time
+	}{
+		// New York, EST -5 / EDT -4, clocks go 02:00 -> 03:00 on 8 March, 02:00 -> 01:00 on 1 November
+		{"new york regular winter", "America/New_York", [5]int{2026, 1, 15, 12, 0}, utc(2026, 1, 15, 17, 0)},
+		{"new york regular summer", "America/New_York", [5]int{2026, 7, 1, 12, 0}, utc(2026, 7, 1, 16, 0)},
+		{"new york before gap", "America/New_York", [5]int{2026, 3, 8, 1, 59}, utc(2026, 3, 8, 6, 59)},
+		{"new york gap start", "America/New_York", [5]int{2026, 3, 8, 2, 0}, utc(2026, 3, 8, 7, 0)},
+		{"new york in gap", "America/New_York", [5]int{2026, 3, 8, 2, 30}, utc(2026, 3, 8, 7, 30)},
+		{"new york after gap", "America/New_York", [5]int{2026, 3, 8, 3, 0}, utc(2026, 3, 8, 7, 0)},
+		{"new york before overlap", "America/New_York", [5]int{2026, 11, 1, 0, 59}, utc(2026, 11, 1, 4, 59)},
+		{"new york overlap start", "America/New_York", [5]int{2026, 11, 1, 1, 0}, utc(2026, 11, 1, 5, 0)},
+		{"new york in overlap", "America/New_York", [5]int{2026, 11, 1, 1, 30}, utc(2026, 11, 1, 5, 30)},
+		{"new york after overlap", "America/New_York", [5]int{2026, 11, 1, 2, 0}, utc(2026, 11, 1, 7, 0)},
+
+		// Lord Howe, +10:30 / +11, clocks go 02:00 -> 02:30 on 4 October, 02:00 -> 01:30 on 5 April
+		{"lord howe in gap", "Australia/Lord_Howe", [5]int{2026, 10, 4, 2, 15}, utc(2026, 10, 3, 15, 45)},
+		{"lord howe after gap", "Australia/Lord_Howe", [5]int{2026, 10, 4, 2, 30}, utc(2026, 10, 3, 15, 30)},
+		{"lord howe before overlap", "Australia/Lord_Howe", [5]int{2026, 4, 5, 1, 29}, utc(2026, 4, 4, 14, 29)},
+		{"lord howe in overlap", "Australia/Lord_Howe", [5]int{2026, 4, 5, 1, 45}, utc(2026, 4, 4, 14, 45)},
+		{"lord howe after overlap", "Australia/Lord_Howe", [5]int{2026, 4, 5, 2, 0}, utc(2026, 4, 4, 15, 30)},
+
+		// Santiago, -4 / -3, clocks go 00:00 -> 01:00 on 6 September, 00:00 -> 23:00 on 5 April
+		{"santiago midnight gap", "America/Santiago", [5]int{2026, 9, 6, 0, 0}, utc(2026, 9, 6, 4, 0)},
+		{"santiago in gap", "America/Santiago", [5]int{2026, 9, 6, 0, 30}, utc(2026, 9, 6, 4, 30)},
+		{"santiago after gap", "America/Santiago", [5]int{2026, 9, 6, 1, 0}, utc(2026, 9, 6, 4, 0)},
+		{"santiago in overlap", "America/Santiago", [5]int{2026, 4, 4, 23, 30}, utc(2026, 4, 5, 2, 30)},
+		{"santiago after overlap", "America/Santiago", [5]int{2026, 4, 5, 0, 0}, utc(2026, 4, 5, 4, 0)},
+
+		{"utc", "UTC", [5]int{2026, 3, 8, 2, 30}, utc(2026, 3, 8, 2, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoadLocation(t, tt.zone)
+			got := CivilTime(tt.local[0], time.Month(tt.local[1]), tt.local[2], tt.local[3], tt.local[4], 0, 0, loc)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("CivilTime = %v (%v), want %v", got.UTC(), got, tt.want)
+			}
+
+			if got.Location() != loc {
+				t.Errorf("CivilTime location = %v, want %v", got.Location(), loc)
+			}
+		})
+	}
+}
+
+func TestCivilTimeNormalizes(t *testing.T) {
+	// out of range values carry over like time.Date
+	got := CivilTime(2026, 2, 29, 24, 0, 0, 0, time.UTC)
+
+	if want := utc(2026, 3, 2, 0, 0); !got.Equal(want) {
+		t.Errorf("CivilTime = %v, want %v", got, want)
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+		date [3]int
+		want time.Time
+	}{
+		{"new york gap day", "America/New_York", [3]int{2026, 3, 8}, utc(2026, 3, 8, 5, 0)},
+		{"new york overlap day", "America/New_York", [3]int{2026, 11, 1}, utc(2026, 11, 1, 4, 0)},
+		{"lord howe gap day", "Australia/Lord_Howe", [3]int{2026, 10, 4}, utc(2026, 10, 3, 13, 30)},
+		{"santiago skipped midnight", "America/Santiago", [3]int{2026, 9, 6}, utc(2026, 9, 6, 4, 0)},
+		{"santiago repeated hour day", "America/Santiago", [3]int{2026, 4, 5}, utc(2026, 4, 5, 4, 0)},
+		{"santiago day before repeated hour", "America/Santiago", [3]int{2026, 4, 4}, utc(2026, 4, 4, 3, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoadLocation(t, tt.zone)
+			got := StartOfDay(tt.date[0], time.Month(tt.date[1]), tt.date[2], loc)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("StartOfDay = %v (%v), want %v", got.UTC(), got, tt.want)
+			}
+
+			if y, m, d := got.Date(); y != tt.date[0] || int(m) != tt.date[1] || d != tt.date[2] {
+				t.Errorf("StartOfDay = %v is not on %v", got, tt.date)
+			}
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Asia/Jakarta", false},
+		{"UTC", false},
+		{"", true},
+		{"Local", true},
+		{"Mars/Olympus_Mons", true},
+		{"../../etc/passwd", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTimeZone(tt.name)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadTimeZone(%q) error = %v, want error %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package application
+
+import (
+	"log"
+	"sync"
+
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+)
+
+type currencyPair struct {
+	fromCurrency string
+	toCurrency   string
+}
+
+type rateSubscriber struct {
+	rates chan dbank.ExchangeRate
+}
+
+// ExchangeRateBroadcaster fans out published exchange rates to the subscribers of each
+// currency pair. A rate is only sent when it differs from the last rate of its pair, and a new
+// subscriber gets the last known rate right away.
+//
+// Only the latest rate is worth delivering, so a slow subscriber never blocks Publish : when
+// its buffer is full, the pending rate is replaced by the newer one.
+type ExchangeRateBroadcaster struct {
+	mu          sync.Mutex
+	lastRates   map[currencyPair]dbank.ExchangeRate
+	subscribers map[currencyPair]map[*rateSubscriber]struct{}
+}
+
+func NewExchangeRateBroadcaster() *ExchangeRateBroadcaster {
+	return &ExchangeRateBroadcaster{
+		lastRates:   map[currencyPair]dbank.ExchangeRate{},
+		subscribers: map[currencyPair]map[*rateSubscriber]struct{}{},
+	}
+}
+
+// Publish sends r to the subscribers of its currency pair, unless the rate is unchanged.
+func (b *ExchangeRateBroadcaster) Publish(r dbank.ExchangeRate) {
+	b.publish(r, false)
+}
+
+// Seed publishes r only when no rate is known yet for its currency pair, e.g. a rate loaded
+// from the database after a restart.
+func (b *ExchangeRateBroadcaster) Seed(r dbank.ExchangeRate) {
+	b.publish(r, true)
+}
+
+func (b *ExchangeRateBroadcaster) publish(r dbank.ExchangeRate, onlyIfUnknown bool) {
+	pair := currencyPair{fromCurrency: r.FromCurrency, toCurrency: r.ToCurrency}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	last, found := b.lastRates[pair]
+
+	if found && onlyIfUnknown {
+		return
+	}
+
+	b.lastRates[pair] = r
+	metrics.ExchangeRateUpdated(r.FromCurrency, r.ToCurrency, r.ValidFromTimestamp)
+
+	if found && last.Rate.Equal(r.Rate) {
+		return
+	}
+
+	for sub := range b.subscribers[pair] {
+		sub.send(r)
+	}
+}
+
+// LastRate returns the last published rate for the currency pair.
+func (b *ExchangeRateBroadcaster) LastRate(fromCur string, toCur string) (dbank.ExchangeRate, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r, found := b.lastRates[currencyPair{fromCurrency: fromCur, toCurrency: toCur}]
+
+	return r, found
+}
+
+// Subscribe returns a channel receiving rates of the currency pair, starting with the last
+// known one. The unsubscribe function must be called once the channel is no longer read; it
+// closes the channel.
+func (b *ExchangeRateBroadcaster) Subscribe(fromCur string, toCur string) (<-chan dbank.ExchangeRate, func()) {
+	pair := currencyPair{fromCurrency: fromCur, toCurrency: toCur}
+	sub := &rateSubscriber{rates: make(chan dbank.ExchangeRate, 1)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[pair] == nil {
+		b.subscribers[pair] = map[*rateSubscriber]struct{}{}
+	}
+
+	b.subscribers[pair][sub] = struct{}{}
+
+	if last, found := b.lastRates[pair]; found {
+		sub.send(last)
+	}
+
+	var once sync.Once
+
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[pair], sub)
+
+			if len(b.subscribers[pair]) == 0 {
+				delete(b.subscribers, pair)
+			}
+
+			close(sub.rates)
+		})
+	}
+
+	return sub.rates, unsubscribe
+}
+
+// send delivers r without blocking, replacing the rate still pending for a slow subscriber.
+// It must be called with the broadcaster lock held.
+func (s *rateSubscriber) send(r dbank.ExchangeRate) {
+	for {
+		select {
+		case s.rates <- r:
+			return
+		default:
+		}
+
+		select {
+		case stale := <-s.rates:
+			log.Printf("Slow exchange rate subscriber, dropped rate %v to %v : %v\n",
+				stale.FromCurrency, stale.ToCurrency, stale.Rate)
+		default:
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package application
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+func usdIdr(rate string) dbank.ExchangeRate {
+	return dbank.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: decimal.RequireFromString(rate),
+		ValidFromTimestamp: time.Now()}
+}
+
+// received returns the rates pending on rates, without waiting for more.
+func received(rates <-chan dbank.ExchangeRate) []string {
+	var res []string
+
+	for {
+		select {
+		case r, ok := <-rates:
+			if !ok {
+				return append(res, "closed")
+			}
+
+			res = append(res, r.Rate.String())
+		default:
+			return res
+		}
+	}
+}
+
+func equalRates(got []string, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestExchangeRateBroadcaster(t *testing.T) {
+	tests := []struct {
+		name string
+		// before are published before subscribing, after once subscribed
+		before []string
+		after  []string
+		want   []string
+	}{
+		{"no rate yet", nil, nil, nil},
+		{"last rate on subscribe", []string{"15000", "15100"}, nil, []string{"15100"}},
+		{"new rate", nil, []string{"15000"}, []string{"15000"}},
+		{"unchanged rate", []string{"15000"}, []string{"15000.00"}, []string{"15000"}},
+		{"slow subscriber gets the newest rate", []string{"15000"}, []string{"15100", "15200"},
+			[]string{"15200"}},
+		{"changed back", nil, []string{"15000", "15100", "15000"}, []string{"15000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewExchangeRateBroadcaster()
+
+			for _, r := range tt.before {
+				b.Publish(usdIdr(r))
+			}
+
+			rates, unsubscribe := b.Subscribe("USD", "IDR")
+			defer unsubscribe()
+
+			for _, r := range tt.after {
+				b.Publish(usdIdr(r))
+			}
+
+			if got := received(rates); !equalRates(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeRateBroadcasterEmitsChangesOnly(t *testing.T) {
+	b := NewExchangeRateBroadcaster()
+	rates, unsubscribe := b.Subscribe("USD", "IDR")
+	defer unsubscribe()
+
+	for _, step := range []struct {
+		rate string
+		want []string
+	}{
+		{"15000", []string{"15000"}},
+		{"15000", nil},
+		{"15100", []string{"15100"}},
+		{"15100.0", nil},
+	} {
+		b.Publish(usdIdr(step.rate))
+
+		if got := received(rates); !equalRates(got, step.want) {
+			t.Errorf("after publishing %v, received %v, want %v", step.rate, got, step.want)
+		}
+	}
+
+	// other pairs have their own subscribers
+	b.Publish(dbank.ExchangeRate{FromCurrency: "EUR", ToCurrency: "IDR", Rate: decimal.NewFromInt(17000)})
+
+	if got := received(rates); len(got) != 0 {
+		t.Errorf("received %v from another pair, want nothing", got)
+	}
+}
+
+func TestExchangeRateBroadcasterSeed(t *testing.T) {
+	b := NewExchangeRateBroadcaster()
+	b.Seed(usdIdr("15000"))
+	b.Seed(usdIdr("14000"))
+
+	if r, _ := b.LastRate("USD", "IDR"); !r.Rate.Equal(decimal.NewFromInt(15000)) {
+		t.Errorf("LastRate = %v, want the first seed 15000", r.Rate)
+	}
+
+	b.Publish(usdIdr("15100"))
+	b.Seed(usdIdr("14000"))
+
+	if r, _ := b.LastRate("USD", "IDR"); !r.Rate.Equal(decimal.NewFromInt(15100)) {
+		t.Errorf("LastRate = %v, want the published 15100", r.Rate)
+	}
+}
+
+func TestExchangeRateBroadcasterUnsubscribe(t *testing.T) {
+	b := NewExchangeRateBroadcaster()
+	b.Publish(usdIdr("15000"))
+
+	rates, unsubscribe := b.Subscribe("USD", "IDR")
+	unsubscribe()
+	unsubscribe()
+
+	// the pending rate is still delivered before the close
+	if got, want := received(rates), []string{"15000", "closed"}; !equalRates(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+
+	b.Publish(usdIdr("15100"))
+
+	if len(b.subscribers) != 0 {
+		t.Errorf("%v pairs still subscribed, want none", len(b.subscribers))
+	}
+}
+
+// TestExchangeRateBroadcasterConcurrency is meant for -race : subscribers come and go while
+// rates are published.
+func TestExchangeRateBroadcasterConcurrency(t *testing.T) {
+	b := NewExchangeRateBroadcaster()
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				rates, unsubscribe := b.Subscribe("USD", "IDR")
+
+				// some subscribers read, others leave their rate pending
+				select {
+				case <-rates:
+				default:
+				}
+
+				unsubscribe()
+
+				for range rates {
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		b.Publish(usdIdr(decimal.NewFromInt(int64(15000 + i%7)).String()))
+	}
+
+	close(stop)
+	wg.Wait()
+
+	if len(b.subscribers) != 0 {
+		t.Errorf("%v pairs still subscribed, want none", len(b.subscribers))
+	}
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+)
+
+// ExchangeRateUpdater stores the rates fetched from an exchange rate provider, refreshing
+// every currency pair on its own interval.
+type ExchangeRateUpdater struct {
+	provider    port.ExchangeRateProviderPort
+	bankService *BankService
+	pairs       []dbank.ExchangeRatePair
+}
+
+func NewExchangeRateUpdater(provider port.ExchangeRateProviderPort, bankService *BankService,
+	pairs []dbank.ExchangeRatePair) *ExchangeRateUpdater {
+	return &ExchangeRateUpdater{
+		provider:    provider,
+		bankService: bankService,
+		pairs:       pairs,
+	}
+}
+
+// Run refreshes every pair right away and then on its interval, until ctx is done.
+func (u *ExchangeRateUpdater) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, pair := range u.pairs {
+		wg.Add(1)
+
+		go func(pair dbank.ExchangeRatePair) {
+			defer wg.Done()
+			u.runPair(ctx, pair)
+		}(pair)
+	}
+
+	wg.Wait()
+}
+
+func (u *ExchangeRateUpdater) runPair(ctx context.Context, pair dbank.ExchangeRatePair) {
+	ticker := time.NewTicker(pair.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		u.refresh(ctx, pair)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *ExchangeRateUpdater) refresh(ctx context.Context, pair dbank.ExchangeRatePair) {
+	rate, err := u.provider.FetchExchangeRate(ctx, pair.FromCurrency, pair.ToCurrency)
+
+	if err != nil {
+		log.Printf("Can't fetch exchange rate %v to %v : %v\n", pair.FromCurrency, pair.ToCurrency, err)
+		return
+	}
+
+	validFrom := time.Now().Truncate(time.Second)
+
+	r := dbank.ExchangeRate{
+		FromCurrency:       pair.FromCurrency,
+		ToCurrency:         pair.ToCurrency,
+		Rate:               rate,
+		ValidFromTimestamp: validFrom,
+		ValidToTimestamp:   validFrom.Add(pair.ValidFor).Add(-1 * time.Millisecond),
+	}
+
+	if _, err := u.bankService.CreateExchangeRate(ctx, r); err != nil {
+		log.Printf("Can't store exchange rate %v to %v : %v\n", pair.FromCurrency, pair.ToCurrency, err)
+	}
+}
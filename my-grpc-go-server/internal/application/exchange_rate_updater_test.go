@@ -0,0 +1,200 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+// fakeRateResult is the answer of fakeRateProvider to one fetch.
+type fakeRateResult struct {
+	rate string
+	err  error
+}
+
+// fakeRateProvider answers each fetch with the next result sent on results, waiting for it.
+// Without results, it answers every fetch with rate at once. Fetches are recorded by pair.
+type fakeRateProvider struct {
+	results chan fakeRateResult
+	rate    decimal.Decimal
+
+	mu      sync.Mutex
+	fetches map[string][]time.Time
+	// fetched receives the pair of every fetch when set
+	fetched chan string
+}
+
+func (p *fakeRateProvider) FetchExchangeRate(ctx context.Context, fromCur string,
+	toCur string) (decimal.Decimal, error) {
+	pair := fromCur + "/" + toCur
+
+	p.mu.Lock()
+	p.fetches[pair] = append(p.fetches[pair], time.Now())
+	p.mu.Unlock()
+
+	if p.fetched != nil {
+		p.fetched <- pair
+	}
+
+	if p.results == nil {
+		return p.rate, nil
+	}
+
+	select {
+	case r := <-p.results:
+		if r.err != nil {
+			return decimal.Zero, r.err
+		}
+
+		return decimal.RequireFromString(r.rate), nil
+	case <-ctx.Done():
+		return decimal.Zero, ctx.Err()
+	}
+}
+
+func (p *fakeRateProvider) fetchTimes(pair string) []time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]time.Time(nil), p.fetches[pair]...)
+}
+
+// runUpdater runs an updater of pairs until the test ends.
+func runUpdater(t *testing.T, p *fakeRateProvider, s *BankService, pairs ...dbank.ExchangeRatePair) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		NewExchangeRateUpdater(p, s, pairs).Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestExchangeRateUpdaterRefreshInterval(t *testing.T) {
+	p := &fakeRateProvider{rate: decimal.NewFromInt(15000), fetches: map[string][]time.Time{}}
+	s := NewBankService(newFakeBankDatabase())
+	interval := 30 * time.Millisecond
+	start := time.Now()
+
+	runUpdater(t, p, s,
+		dbank.ExchangeRatePair{FromCurrency: "USD", ToCurrency: "IDR", RefreshInterval: interval, ValidFor: interval},
+		dbank.ExchangeRatePair{FromCurrency: "EUR", ToCurrency: "IDR", RefreshInterval: time.Hour, ValidFor: time.Hour},
+	)
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for len(p.fetchTimes("USD/IDR")) < 4 && time.Now().Before(deadline) {
+		time.Sleep(interval / 3)
+	}
+
+	fetches := p.fetchTimes("USD/IDR")
+
+	if len(fetches) < 4 {
+		t.Fatalf("USD/IDR fetched %v times, want 4", len(fetches))
+	}
+
+	if first := fetches[0].Sub(start); first > interval/2 {
+		t.Errorf("first USD/IDR fetch after %v, want it right away", first)
+	}
+
+	for i := 1; i < len(fetches); i++ {
+		// ticks are late rather than early, a tick is dropped when the refresh is slow
+		if gap := fetches[i].Sub(fetches[i-1]); gap < interval/2 {
+			t.Errorf("USD/IDR fetch %v came %v after the previous one, want about %v", i, gap, interval)
+		}
+	}
+
+	// each pair has its own interval
+	if got := len(p.fetchTimes("EUR/IDR")); got != 1 {
+		t.Errorf("EUR/IDR fetched %v times, want only the first fetch", got)
+	}
+
+	r, found := s.exchangeRates.LastRate("USD", "IDR")
+
+	if !found || !r.Rate.Equal(p.rate) {
+		t.Fatalf("LastRate = %v, %v, want %v", r.Rate, found, p.rate)
+	}
+
+	if validFor := r.ValidToTimestamp.Sub(r.ValidFromTimestamp); validFor != interval-time.Millisecond {
+		t.Errorf("rate valid for %v, want %v", validFor, interval-time.Millisecond)
+	}
+}
+
+func TestExchangeRateUpdaterPublishes(t *testing.T) {
+	p := &fakeRateProvider{
+		results: make(chan fakeRateResult),
+		fetches: map[string][]time.Time{},
+		fetched: make(chan string, 10),
+	}
+	fake := newFakeBankDatabase()
+	s := NewBankService(fake)
+
+	rates, unsubscribe, err := s.SubscribeExchangeRates(context.Background(), "USD", "IDR")
+
+	if err != nil {
+		t.Fatalf("SubscribeExchangeRates : %v", err)
+	}
+
+	defer unsubscribe()
+
+	runUpdater(t, p, s, dbank.ExchangeRatePair{FromCurrency: "USD", ToCurrency: "IDR",
+		RefreshInterval: 10 * time.Millisecond, ValidFor: time.Minute})
+
+	for _, step := range []struct {
+		result fakeRateResult
+		// want is the rate published, the last one is kept when the fetch fails
+		want string
+	}{
+		{fakeRateResult{rate: "15000"}, "15000"},
+		{fakeRateResult{err: errors.New("provider unavailable")}, ""},
+		{fakeRateResult{rate: "15000"}, ""},
+		{fakeRateResult{rate: "15100"}, "15100"},
+	} {
+		<-p.fetched
+		p.results <- step.result
+
+		if step.want == "" {
+			// the refresh is done once the next fetch starts, which the next step waits for
+			p.fetched <- <-p.fetched
+
+			select {
+			case r := <-rates:
+				t.Errorf("published %v, want nothing", r.Rate)
+			default:
+			}
+
+			if r, _ := s.exchangeRates.LastRate("USD", "IDR"); !r.Rate.Equal(decimal.NewFromInt(15000)) {
+				t.Errorf("LastRate = %v, want 15000 kept", r.Rate)
+			}
+
+			continue
+		}
+
+		select {
+		case r := <-rates:
+			if !r.Rate.Equal(decimal.RequireFromString(step.want)) {
+				t.Errorf("published %v, want %v", r.Rate, step.want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("rate %v not published", step.want)
+		}
+
+		stored, err := fake.GetExchangeRateAtTimestamp(context.Background(), "USD", "IDR", time.Now())
+
+		if err != nil || !stored.Rate.Equal(decimal.RequireFromString(step.want)) {
+			t.Errorf("stored rate = %v, %v, want %v", stored.Rate, err, step.want)
+		}
+	}
+}
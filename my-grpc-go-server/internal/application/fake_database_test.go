@@ -0,0 +1,256 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+	"gorm.io/gorm"
+)
+
+// fakeBankDatabase keeps bank accounts, transactions and transfers in memory. Methods not overridden panic
+// through the nil embedded port, a test calling them has to override them.
+type fakeBankDatabase struct {
+	port.BankDatabasePort
+
+	mu           sync.Mutex
+	accounts     map[string]db.BankAccountOrm
+	transactions []db.BankTransactionOrm
+	transfers    map[string]db.BankTransferOrm
+	// rates are the exchange rates by from and to currency, e.g. USD/EUR
+	rates map[string]db.BankExchangeRateOrm
+	// pairErr is returned by CreateTransferTransactionPair
+	pairErr error
+	// createAccountErrs are returned by the next CreateAccount calls, one per call
+	createAccountErrs []error
+	createdAccounts   []string
+}
+
+func newFakeBankDatabase(accounts ...db.BankAccountOrm) *fakeBankDatabase {
+	f := &fakeBankDatabase{
+		accounts:  map[string]db.BankAccountOrm{},
+		transfers: map[string]db.BankTransferOrm{},
+		rates:     map[string]db.BankExchangeRateOrm{},
+	}
+
+	for _, a := range accounts {
+		f.accounts[a.AccountNumber] = a
+	}
+
+	return f
+}
+
+func (f *fakeBankDatabase) GetBankAccountByAccountNumber(ctx context.Context,
+	acct string) (db.BankAccountOrm, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	a, found := f.accounts[acct]
+
+	if !found {
+		return db.BankAccountOrm{}, gorm.ErrRecordNotFound
+	}
+
+	return a, nil
+}
+
+// CreateExchangeRate keeps the latest rate of the currency pair.
+func (f *fakeBankDatabase) CreateExchangeRate(ctx context.Context, r db.BankExchangeRateOrm) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rates[r.FromCurrency+"/"+r.ToCurrency] = r
+
+	return r.ExchangeRateUuid, nil
+}
+
+func (f *fakeBankDatabase) GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+	ts time.Time) (db.BankExchangeRateOrm, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, found := f.rates[fromCur+"/"+toCur]
+
+	if !found {
+		return db.BankExchangeRateOrm{}, gorm.ErrRecordNotFound
+	}
+
+	return r, nil
+}
+
+func (f *fakeBankDatabase) IsAccountNumberExist(ctx context.Context, acct string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, found := f.accounts[acct]
+
+	return found, nil
+}
+
+func (f *fakeBankDatabase) CreateAccount(ctx context.Context, acct db.BankAccountOrm,
+	initialDeposit *db.BankTransactionOrm) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.createdAccounts = append(f.createdAccounts, acct.AccountNumber)
+
+	if len(f.createAccountErrs) > 0 {
+		err := f.createAccountErrs[0]
+		f.createAccountErrs = f.createAccountErrs[1:]
+
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	f.accounts[acct.AccountNumber] = acct
+
+	return acct.AccountUuid, nil
+}
+
+func (f *fakeBankDatabase) CreateTransaction(ctx context.Context, acct db.BankAccountOrm,
+	t db.BankTransactionOrm, periods dbank.OutgoingPeriods) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.transactions = append(f.transactions, t)
+
+	return t.TransactionUuid, nil
+}
+
+func (f *fakeBankDatabase) GetTransactionByIdempotencyKey(ctx context.Context,
+	key string) (db.BankTransactionOrm, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, t := range f.transactions {
+		if t.IdempotencyKey != nil && *t.IdempotencyKey == key {
+			return t, true, nil
+		}
+	}
+
+	return db.BankTransactionOrm{}, false, nil
+}
+
+// ListTransactions filters and orders the transactions as the database adapter query does.
+func (f *fakeBankDatabase) ListTransactions(ctx context.Context,
+	filter db.BankTransactionFilter) ([]db.BankTransactionOrm, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res := []db.BankTransactionOrm{}
+
+	for _, t := range f.transactions {
+		if t.AccountUuid != filter.AccountUuid ||
+			(!filter.FromTimestamp.IsZero() && t.ValueTimestamp.Before(filter.FromTimestamp)) ||
+			(!filter.ToTimestamp.IsZero() && !t.ValueTimestamp.Before(filter.ToTimestamp)) ||
+			(filter.TransactionType != "" && t.TransactionType != filter.TransactionType) {
+			continue
+		}
+
+		if filter.After != nil && !transactionBefore(t, filter.After.ValueTimestamp, filter.After.TransactionUuid) {
+			continue
+		}
+
+		res = append(res, t)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return transactionBefore(res[j], res[i].ValueTimestamp, res[i].TransactionUuid)
+	})
+
+	if filter.Limit > 0 && len(res) > filter.Limit {
+		res = res[:filter.Limit]
+	}
+
+	return res, nil
+}
+
+// transactionBefore tells whether t sorts before the value timestamp and uuid, by value timestamp
+// then uuid.
+func transactionBefore(t db.BankTransactionOrm, valueTimestamp time.Time, transactionUuid uuid.UUID) bool {
+	if !t.ValueTimestamp.Equal(valueTimestamp) {
+		return t.ValueTimestamp.Before(valueTimestamp)
+	}
+
+	return bytes.Compare(t.TransactionUuid[:], transactionUuid[:]) < 0
+}
+
+func (f *fakeBankDatabase) CreateTransfer(ctx context.Context, t db.BankTransferOrm) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if t.IdempotencyKey != nil {
+		f.transfers[*t.IdempotencyKey] = t
+	}
+
+	return t.TransferUuid, nil
+}
+
+func (f *fakeBankDatabase) GetTransferByIdempotencyKey(ctx context.Context,
+	key string) (db.BankTransferOrm, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, found := f.transfers[key]
+
+	return t, found, nil
+}
+
+// RunInTransaction gives fn a unit of work whose writes are dropped, as if rolled back, when fn
+// fails.
+func (f *fakeBankDatabase) RunInTransaction(ctx context.Context, fn func(uow db.BankUnitOfWork) error) error {
+	uow := &fakeBankUnitOfWork{db: f}
+
+	if err := fn(uow); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, t := range uow.transfers {
+		if t.IdempotencyKey != nil {
+			f.transfers[*t.IdempotencyKey] = t
+		}
+	}
+
+	return nil
+}
+
+type fakeBankUnitOfWork struct {
+	db        *fakeBankDatabase
+	transfers []db.BankTransferOrm
+}
+
+func (u *fakeBankUnitOfWork) CreateTransfer(ctx context.Context, t db.BankTransferOrm) (uuid.UUID, error) {
+	u.transfers = append(u.transfers, t)
+
+	return t.TransferUuid, nil
+}
+
+func (u *fakeBankUnitOfWork) CreateTransferTransactionPair(ctx context.Context, fromAccountOrm db.BankAccountOrm,
+	toAccountOrm db.BankAccountOrm, fromTransactionOrm db.BankTransactionOrm,
+	toTransactionOrm db.BankTransactionOrm, periods dbank.OutgoingPeriods) (bool, error) {
+	if u.db.pairErr != nil {
+		return false, u.db.pairErr
+	}
+
+	return true, nil
+}
+
+func (u *fakeBankUnitOfWork) UpdateTransferStatus(ctx context.Context, t db.BankTransferOrm, status bool) error {
+	for i := range u.transfers {
+		if u.transfers[i].TransferUuid == t.TransferUuid {
+			u.transfers[i].TransferSuccess = status
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package application
 
+import "context"
+
 type HelloService struct {
 }
 
-func (a *HelloService) GenerateHello(name string) string {
+func (a *HelloService) GenerateHello(ctx context.Context, name string) string {
 	return "Hello " + name
 }
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"time"
@@ -9,15 +10,21 @@ import (
 type ResiliencyService struct {
 }
 
-func (r *ResiliencyService) GenerateResiliency(minDelaySecond int32, maxDelaySecond int32,
-	statusCodes []uint32) (string, uint32) {
+func (r *ResiliencyService) GenerateResiliency(ctx context.Context, minDelaySecond int32,
+	maxDelaySecond int32, statusCodes []uint32) (string, uint32, error) {
 	delay := rand.Intn(int(maxDelaySecond-minDelaySecond+1)) + int(minDelaySecond)
-	delaySecond := time.Duration(delay) * time.Second
-	time.Sleep(delaySecond)
+	timer := time.NewTimer(time.Duration(delay) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return "", 0, ctx.Err()
+	case <-timer.C:
+	}
 
 	idx := rand.Intn(len(statusCodes))
 	str := fmt.Sprintf("The time now is %v, execution delayed for %v seconds",
 		time.Now().Format("15:04:05.000"), delay)
 
-	return str, statusCodes[idx]
+	return str, statusCodes[idx], nil
 }
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateResiliencyStopsWithContext(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want error
+	}{
+		{"cancelled", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			return ctx, cancel
+		}, context.Canceled},
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 50*time.Millisecond)
+		}, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, _, err := (&ResiliencyService{}).GenerateResiliency(ctx, 60, 60, []uint32{0})
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+
+			// the 60 seconds delay is cut short
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("GenerateResiliency returned after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestGenerateResiliencyWithoutDelay(t *testing.T) {
+	str, code, err := (&ResiliencyService{}).GenerateResiliency(context.Background(), 0, 0, []uint32{3})
+
+	if err != nil || code != 3 || str == "" {
+		t.Errorf("GenerateResiliency = %q, %v, %v, want a string, 3, nil", str, code, err)
+	}
+}
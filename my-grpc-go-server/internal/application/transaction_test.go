@@ -0,0 +1,370 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+func TestCreateTransactionValueDate(t *testing.T) {
+	day := 24 * time.Hour
+
+	// offsets stay a minute away from the window edges, the service reads the clock itself
+	tests := []struct {
+		name   string
+		window *dbank.ValueDateWindow
+		offset time.Duration
+		// zero is set for a transaction sent without a value date
+		zero    bool
+		wantErr error
+	}{
+		{name: "no value date", zero: true},
+		{name: "now", offset: 0},
+		{name: "inside past edge", offset: -30*day + time.Minute},
+		{name: "outside past edge", offset: -30*day - time.Minute, wantErr: dbank.ErrValueDateOutOfRange},
+		{name: "inside future edge", offset: day - time.Minute},
+		{name: "outside future edge", offset: day + time.Minute, wantErr: dbank.ErrValueDateOutOfRange},
+		{name: "custom window past", window: &dbank.ValueDateWindow{MaxPast: time.Hour},
+			offset: -time.Hour + time.Minute},
+		{name: "custom window too old", window: &dbank.ValueDateWindow{MaxPast: time.Hour},
+			offset: -time.Hour - time.Minute, wantErr: dbank.ErrValueDateOutOfRange},
+		{name: "custom window no future", window: &dbank.ValueDateWindow{MaxPast: time.Hour},
+			offset: time.Minute, wantErr: dbank.ErrValueDateOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeBankDatabase(testAccount("A1"))
+			s := NewBankService(fake)
+
+			if tt.window != nil {
+				s.SetValueDateWindow(*tt.window)
+			}
+
+			before := time.Now()
+			transaction := dbank.Transaction{Amount: decimal.NewFromInt(10),
+				TransactionType: dbank.TransactionTypeIn}
+
+			if !tt.zero {
+				transaction.Timestamp = before.Add(tt.offset)
+			}
+
+			_, err := s.CreateTransaction(context.Background(), "A1", transaction)
+			after := time.Now()
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("CreateTransaction = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(fake.transactions) != 0 {
+					t.Errorf("stored %v transactions, want none", len(fake.transactions))
+				}
+
+				return
+			}
+
+			if len(fake.transactions) != 1 {
+				t.Fatalf("stored %v transactions, want 1", len(fake.transactions))
+			}
+
+			stored := fake.transactions[0]
+			booked := stored.TransactionTimestamp
+
+			if booked.Before(before) || booked.After(after) {
+				t.Errorf("booking timestamp = %v, want between %v and %v", booked, before, after)
+			}
+
+			wantValue := transaction.Timestamp
+
+			if tt.zero {
+				wantValue = booked
+			}
+
+			if !stored.ValueTimestamp.Equal(wantValue) {
+				t.Errorf("value timestamp = %v, want %v", stored.ValueTimestamp, wantValue)
+			}
+		})
+	}
+}
+
+func TestListTransactionsByValueDate(t *testing.T) {
+	fake := newFakeBankDatabase(testAccount("A1"))
+	s := NewBankService(fake)
+	now := time.Now().UTC()
+
+	// booked in this order, value dated out of order
+	valueDates := map[string]time.Time{
+		"two days back":      now.AddDate(0, 0, -2),
+		"today":              {},
+		"five days back":     now.AddDate(0, 0, -5),
+		"twelve hours ahead": now.Add(12 * time.Hour),
+	}
+	booked := []string{"two days back", "today", "five days back", "twelve hours ahead"}
+
+	for _, notes := range booked {
+		if _, err := s.CreateTransaction(context.Background(), "A1", dbank.Transaction{
+			Amount: decimal.NewFromInt(10), TransactionType: dbank.TransactionTypeIn,
+			Timestamp: valueDates[notes], Notes: notes}); err != nil {
+			t.Fatalf("CreateTransaction %v : %v", notes, err)
+		}
+	}
+
+	storedValueDates := map[string]time.Time{}
+	bookingTimestamps := map[string]time.Time{}
+
+	for _, stored := range fake.transactions {
+		storedValueDates[stored.Notes] = stored.ValueTimestamp
+		bookingTimestamps[stored.Notes] = stored.TransactionTimestamp
+	}
+
+	y, m, d := valueDates["two days back"].Date()
+	twoDaysBack := dbank.StartOfDay(y, m, d, time.UTC)
+
+	tests := []struct {
+		name  string
+		query dbank.TransactionQuery
+		// pages lists the notes of the transactions on each page
+		pages [][]string
+	}{
+		{"latest value date first", dbank.TransactionQuery{},
+			[][]string{{"twelve hours ahead", "today", "two days back", "five days back"}}},
+		{"paged by value date", dbank.TransactionQuery{PageSize: 2},
+			[][]string{{"twelve hours ahead", "today"}, {"two days back", "five days back"}}},
+		{"one value date", dbank.TransactionQuery{FromDate: twoDaysBack, ToDate: twoDaysBack},
+			[][]string{{"two days back"}}},
+		{"up to a value date", dbank.TransactionQuery{ToDate: twoDaysBack},
+			[][]string{{"two days back", "five days back"}}},
+		{"from a value date", dbank.TransactionQuery{FromDate: twoDaysBack, PageSize: 3},
+			[][]string{{"twelve hours ahead", "today", "two days back"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.query
+			q.AccountNumber = "A1"
+
+			for i, want := range tt.pages {
+				page, err := s.ListTransactions(context.Background(), q)
+
+				if err != nil {
+					t.Fatalf("ListTransactions page %v : %v", i, err)
+				}
+
+				if len(page.Transactions) != len(want) {
+					t.Fatalf("page %v has %v transactions, want %v", i, len(page.Transactions), want)
+				}
+
+				for j, trans := range page.Transactions {
+					if trans.Notes != want[j] {
+						t.Errorf("page %v transaction %v = %v, want %v", i, j, trans.Notes, want[j])
+					}
+
+					if !trans.Timestamp.Equal(storedValueDates[trans.Notes]) ||
+						!trans.BookingTimestamp.Equal(bookingTimestamps[trans.Notes]) {
+						t.Errorf("%v : timestamp %v booked %v, want value date %v booked %v", trans.Notes,
+							trans.Timestamp, trans.BookingTimestamp, storedValueDates[trans.Notes],
+							bookingTimestamps[trans.Notes])
+					}
+				}
+
+				if last := i == len(tt.pages)-1; last != (page.NextPageToken == "") {
+					t.Fatalf("page %v next page token = %q, want one only before the last page", i,
+						page.NextPageToken)
+				}
+
+				q.PageToken = page.NextPageToken
+			}
+		})
+	}
+}
+
+func TestCreateTransactionReplayOutsideWindow(t *testing.T) {
+	fake := newFakeBankDatabase(testAccount("A1"))
+	s := NewBankService(fake)
+	s.SetValueDateWindow(dbank.ValueDateWindow{MaxPast: time.Hour})
+
+	transaction := dbank.Transaction{Amount: decimal.NewFromInt(10), TransactionType: dbank.TransactionTypeIn,
+		Timestamp: time.Now().Add(-30 * time.Minute), IdempotencyKey: "backdated"}
+
+	first, err := s.CreateTransaction(context.Background(), "A1", transaction)
+
+	if err != nil {
+		t.Fatalf("CreateTransaction : %v", err)
+	}
+
+	// the value date left the window before the retry
+	s.SetValueDateWindow(dbank.ValueDateWindow{MaxPast: time.Minute})
+
+	tests := []struct {
+		name     string
+		key      string
+		amount   int64
+		wantUuid bool
+		wantErr  error
+	}{
+		{"retry", "backdated", 10, true, nil},
+		{"key reused", "backdated", 11, false, dbank.ErrIdempotencyKeyReused},
+		{"new transaction", "backdated-2", 10, false, dbank.ErrValueDateOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry := transaction
+			retry.IdempotencyKey = tt.key
+			retry.Amount = decimal.NewFromInt(tt.amount)
+
+			got, err := s.CreateTransaction(context.Background(), "A1", retry)
+
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("CreateTransaction = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantUuid && got != first {
+				t.Errorf("CreateTransaction = %v, want the stored transaction %v", got, first)
+			}
+
+			if len(fake.transactions) != 1 {
+				t.Errorf("stored %v transactions, want 1", len(fake.transactions))
+			}
+		})
+	}
+}
+
+func TestListTransactionsPagesToTheEnd(t *testing.T) {
+	fake := newFakeBankDatabase(testAccount("A1"))
+	s := NewBankService(fake)
+	valueDate := time.Now().Add(-time.Hour).UTC()
+
+	// three value dates, ties broken by uuid
+	for i := 0; i < 7; i++ {
+		if _, err := s.CreateTransaction(context.Background(), "A1", dbank.Transaction{
+			Amount: decimal.NewFromInt(10), TransactionType: dbank.TransactionTypeIn,
+			Timestamp: valueDate.Add(time.Duration(i%3) * time.Minute), Notes: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("CreateTransaction %v : %v", i, err)
+		}
+	}
+
+	// latest value date first, then greatest uuid first
+	sorted := append([]db.BankTransactionOrm(nil), fake.transactions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return transactionBefore(sorted[j], sorted[i].ValueTimestamp, sorted[i].TransactionUuid)
+	})
+
+	var want []string
+
+	for _, stored := range sorted {
+		want = append(want, stored.Notes)
+	}
+
+	for _, pageSize := range []int{1, 2, 3, 7, 8} {
+		t.Run(fmt.Sprint("page size ", pageSize), func(t *testing.T) {
+			q := dbank.TransactionQuery{AccountNumber: "A1", PageSize: pageSize}
+			var got []string
+
+			for pages := 1; ; pages++ {
+				page, err := s.ListTransactions(context.Background(), q)
+
+				if err != nil {
+					t.Fatalf("ListTransactions page %v : %v", pages, err)
+				}
+
+				if len(page.Transactions) > pageSize {
+					t.Fatalf("page %v has %v transactions, want up to %v", pages, len(page.Transactions), pageSize)
+				}
+
+				for _, trans := range page.Transactions {
+					got = append(got, trans.Notes)
+				}
+
+				if page.NextPageToken == "" {
+					break
+				}
+
+				if pages > len(want) {
+					t.Fatalf("still paging after %v pages", pages)
+				}
+
+				q.PageToken = page.NextPageToken
+			}
+
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("transactions = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListTransactionsInvalidPageToken(t *testing.T) {
+	fake := newFakeBankDatabase(testAccount("A1"), testAccount("A2"))
+	s := NewBankService(fake)
+
+	for _, acct := range []string{"A1", "A2"} {
+		for i := 0; i < 3; i++ {
+			if _, err := s.CreateTransaction(context.Background(), acct, dbank.Transaction{
+				Amount: decimal.NewFromInt(10), TransactionType: dbank.TransactionTypeIn}); err != nil {
+				t.Fatalf("CreateTransaction : %v", err)
+			}
+		}
+	}
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	first := dbank.TransactionQuery{AccountNumber: "A1", PageSize: 1}
+	page, err := s.ListTransactions(context.Background(), first)
+
+	if err != nil || page.NextPageToken == "" {
+		t.Fatalf("ListTransactions = %v, %v, want a next page", page, err)
+	}
+
+	token := page.NextPageToken
+	raw, _ := base64.RawURLEncoding.DecodeString(token)
+	parts := strings.Split(string(raw), "_")
+	encode := func(s ...string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(s, "_")))
+	}
+
+	tests := []struct {
+		name string
+		// change makes the query of the next page from the query of the first one
+		change func(q *dbank.TransactionQuery)
+	}{
+		{"not base64", func(q *dbank.TransactionQuery) { q.PageToken = "!" + token }},
+		{"missing filters", func(q *dbank.TransactionQuery) { q.PageToken = encode(parts[0], parts[1]) }},
+		{"invalid timestamp", func(q *dbank.TransactionQuery) { q.PageToken = encode("x", parts[1], parts[2]) }},
+		{"invalid uuid", func(q *dbank.TransactionQuery) { q.PageToken = encode(parts[0], "x", parts[2]) }},
+		{"forged filters", func(q *dbank.TransactionQuery) { q.PageToken = encode(parts[0], parts[1], "x") }},
+		{"other account", func(q *dbank.TransactionQuery) { q.AccountNumber = "A2" }},
+		{"other type", func(q *dbank.TransactionQuery) { q.TransactionType = dbank.TransactionTypeOut }},
+		{"other from date", func(q *dbank.TransactionQuery) { q.FromDate = yesterday }},
+		{"other to date", func(q *dbank.TransactionQuery) { q.ToDate = yesterday }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := first
+			q.PageToken = token
+			tt.change(&q)
+
+			if _, err := s.ListTransactions(context.Background(), q); !errors.Is(err, dbank.ErrInvalidPageToken) {
+				t.Errorf("ListTransactions = %v, want %v", err, dbank.ErrInvalidPageToken)
+			}
+		})
+	}
+
+	// another page size keeps the filters
+	next := first
+	next.PageToken, next.PageSize = token, 5
+
+	if _, err := s.ListTransactions(context.Background(), next); err != nil {
+		t.Errorf("ListTransactions with another page size : %v", err)
+	}
+}
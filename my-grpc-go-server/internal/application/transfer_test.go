@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
+)
+
+func testAccount(acct string) db.BankAccountOrm {
+	return db.BankAccountOrm{
+		AccountUuid:    uuid.New(),
+		AccountNumber:  acct,
+		Currency:       "USD",
+		CurrentBalance: decimal.NewFromInt(100),
+		Status:         dbank.AccountStatusActive,
+	}
+}
+
+func TestTransferReplaysOriginalFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairErr error
+		want    error
+	}{
+		{
+			name:    "account status",
+			pairErr: &dbank.AccountStatusError{AccountNumber: "A1", Status: dbank.AccountStatusFrozen},
+			want:    &dbank.AccountStatusError{AccountNumber: "A1", Status: dbank.AccountStatusFrozen},
+		},
+		{
+			name: "account limit",
+			pairErr: &dbank.AccountLimitError{AccountNumber: "A1", Limit: dbank.LimitDailyOutgoing,
+				Allowed: decimal.NewFromInt(50), Used: decimal.NewFromInt(40), Requested: decimal.NewFromInt(20)},
+			want: &dbank.AccountLimitError{AccountNumber: "A1", Limit: dbank.LimitDailyOutgoing,
+				Allowed: decimal.NewFromInt(50), Used: decimal.NewFromInt(40), Requested: decimal.NewFromInt(20)},
+		},
+		{
+			name:    "transaction pair",
+			pairErr: errors.New("connection reset"),
+			want:    dbank.ErrTransferTransactionPair,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeBankDatabase(testAccount("A1"), testAccount("A2"))
+			fake.pairErr = tt.pairErr
+			s := NewBankService(fake)
+
+			transfer := dbank.TransferTransaction{
+				FromAccountNumber: "A1",
+				ToAccountNumber:   "A2",
+				Currency:          "USD",
+				Amount:            decimal.NewFromInt(20),
+				IdempotencyKey:    "key-" + tt.name,
+			}
+
+			_, err := s.Transfer(context.Background(), transfer)
+			assertSameError(t, "transfer", err, tt.want)
+
+			// the retry must not run the transaction pair again
+			fake.pairErr = nil
+
+			res, err := s.Transfer(context.Background(), transfer)
+			assertSameError(t, "replay", err, tt.want)
+
+			if !res.Replayed || res.TransferSuccess {
+				t.Errorf("replay result = %+v, want a replayed failed transfer", res)
+			}
+		})
+	}
+}
+
+func TestTransferReplayWithoutStoredKind(t *testing.T) {
+	key := "legacy-key"
+	from, to := testAccount("A1"), testAccount("A2")
+	fake := newFakeBankDatabase(from, to)
+	fake.transfers[key] = db.BankTransferOrm{
+		TransferUuid:    uuid.New(),
+		FromAccountUuid: from.AccountUuid,
+		ToAccountUuid:   to.AccountUuid,
+		Currency:        "USD",
+		Amount:          decimal.NewFromInt(20),
+		IdempotencyKey:  &key,
+	}
+
+	_, err := NewBankService(fake).Transfer(context.Background(), dbank.TransferTransaction{
+		FromAccountNumber: "A1",
+		ToAccountNumber:   "A2",
+		Currency:          "USD",
+		Amount:            decimal.NewFromInt(20),
+		IdempotencyKey:    key,
+	})
+
+	assertSameError(t, "replay", err, dbank.ErrTransferTransactionPair)
+}
+
+func TestTransferReplayWithoutExchangeRate(t *testing.T) {
+	from, to := testAccount("A1"), testAccount("A2")
+	to.Currency = "EUR"
+	fake := newFakeBankDatabase(from, to)
+	fake.rates["USD/EUR"] = db.BankExchangeRateOrm{ExchangeRateUuid: uuid.New(), FromCurrency: "USD",
+		ToCurrency: "EUR", Rate: decimal.RequireFromString("0.9")}
+	s := NewBankService(fake)
+
+	transfer := dbank.TransferTransaction{
+		FromAccountNumber: "A1",
+		ToAccountNumber:   "A2",
+		Currency:          "USD",
+		Amount:            decimal.NewFromInt(20),
+		IdempotencyKey:    "cross-currency",
+	}
+
+	first, err := s.Transfer(context.Background(), transfer)
+
+	if err != nil || !first.TransferSuccess {
+		t.Fatalf("Transfer = %+v, %v, want a success", first, err)
+	}
+
+	// the rate source is down, or the pair was removed
+	delete(fake.rates, "USD/EUR")
+
+	replay, err := s.Transfer(context.Background(), transfer)
+
+	if err != nil || !replay.Replayed || !replay.TransferSuccess || replay.TransferUuid != first.TransferUuid ||
+		!replay.ExchangeRate.Equal(first.ExchangeRate) {
+		t.Errorf("replay = %+v, %v, want the stored transfer %+v", replay, err, first)
+	}
+
+	transfer.IdempotencyKey = "cross-currency-2"
+
+	_, err = s.Transfer(context.Background(), transfer)
+	assertSameError(t, "new transfer", err, dbank.ErrExchangeRateNotFound)
+}
+
+func TestTransferToSameAccount(t *testing.T) {
+	fake := newFakeBankDatabase(testAccount("A1"))
+	fake.pairErr = errors.New("account row locked twice")
+
+	_, err := NewBankService(fake).Transfer(context.Background(), dbank.TransferTransaction{
+		FromAccountNumber: "A1",
+		ToAccountNumber:   "A1",
+		Currency:          "USD",
+		Amount:            decimal.NewFromInt(20),
+		IdempotencyKey:    "same-account",
+	})
+
+	assertSameError(t, "transfer", err, dbank.ErrTransferSameAccount)
+
+	if len(fake.transfers) != 0 {
+		t.Errorf("stored transfers = %v, want none", fake.transfers)
+	}
+}
+
+// assertSameError checks err is want, or a typed error with the same fields as want.
+func assertSameError(t *testing.T, name string, err error, want error) {
+	t.Helper()
+
+	var statusErr *dbank.AccountStatusError
+	var limitErr *dbank.AccountLimitError
+
+	switch w := want.(type) {
+	case *dbank.AccountStatusError:
+		if !errors.As(err, &statusErr) || !reflect.DeepEqual(statusErr, w) {
+			t.Errorf("%v error = %v, want %v", name, err, want)
+		}
+	case *dbank.AccountLimitError:
+		if !errors.As(err, &limitErr) || limitErr.AccountNumber != w.AccountNumber || limitErr.Limit != w.Limit ||
+			!limitErr.Allowed.Equal(w.Allowed) || !limitErr.Used.Equal(w.Used) ||
+			!limitErr.Requested.Equal(w.Requested) {
+			t.Errorf("%v error = %v, want %v", name, err, want)
+		}
+	default:
+		if !errors.Is(err, want) {
+			t.Errorf("%v error = %v, want %v", name, err, want)
+		}
+	}
+}
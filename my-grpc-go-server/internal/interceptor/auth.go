@@ -0,0 +1,88 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/port"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthUnaryServerInterceptor authenticates the callers of the protected services, e.g.
+// bank.BankService, and puts their principal in the handler context. Other services stay
+// public.
+func AuthUnaryServerInterceptor(authn port.AuthenticatorPort, protectedServices ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if !isProtected(info.FullMethod, protectedServices) {
+			return handler(ctx, req)
+		}
+
+		p, err := authn.Authenticate(ctx)
+
+		if err != nil {
+			return nil, unauthenticatedStatus(ctx, info.FullMethod, err)
+		}
+
+		return handler(dauth.NewContext(ctx, p), req)
+	}
+}
+
+// AuthStreamServerInterceptor authenticates the callers of the protected services, e.g.
+// bank.BankService, and puts their principal in the stream context.
+func AuthStreamServerInterceptor(authn port.AuthenticatorPort, protectedServices ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if !isProtected(info.FullMethod, protectedServices) {
+			return handler(srv, ss)
+		}
+
+		p, err := authn.Authenticate(ss.Context())
+
+		if err != nil {
+			return unauthenticatedStatus(ss.Context(), info.FullMethod, err)
+		}
+
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          dauth.NewContext(ss.Context(), p),
+		})
+	}
+}
+
+func isProtected(fullMethod string, protectedServices []string) bool {
+	service, _ := splitMethodName(fullMethod)
+
+	for _, s := range protectedServices {
+		if s == service {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unauthenticatedStatus(ctx context.Context, method string, err error) error {
+	log.Printf("Unauthenticated call to %v (request id %v) : %v\n", method, RequestIdFromContext(ctx), err)
+
+	reason := "CREDENTIALS_MISSING"
+
+	if errors.Is(err, dauth.ErrInvalidCredentials) {
+		reason = "CREDENTIALS_INVALID"
+	}
+
+	// the detailed error stays in the server log, it may tell why a forged token was refused
+	s := status.New(codes.Unauthenticated, strings.SplitN(err.Error(), " : ", 2)[0])
+	s, _ = s.WithDetails(&errdetails.ErrorInfo{
+		Domain: "my-bank-website.com",
+		Reason: reason,
+	})
+
+	return s.Err()
+}
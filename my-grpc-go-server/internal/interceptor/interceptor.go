@@ -2,122 +2,52 @@ package interceptor
 
 import (
 	"context"
-	"log"
-
-	hello_proto "github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
-	resl_proto "github.com/timpamungkas/my-grpc-proto/protogen/go/resiliency"
 
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 )
 
-func LogUnaryServerInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{},
-		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		log.Println("[LOGGED BY SERVER INTERCEPTOR]", req)
-
-		return handler(ctx, req)
+// ServerOptions returns the server interceptor chain enabled by cfg, outermost first : tracing,
+// so the RPC span covers the whole chain, then request id, so every log line carries it, then
+// request logging and metrics, then panic recovery, so a recovered panic is logged and counted
+// with its Internal status. It must be called once the tracer provider is set up.
+func ServerOptions(cfg config.GrpcConfig) []grpc.ServerOption {
+	var unaries []grpc.UnaryServerInterceptor
+	var streams []grpc.StreamServerInterceptor
+
+	if cfg.Tracing {
+		unaries = append(unaries, otelgrpc.UnaryServerInterceptor())
+		streams = append(streams, otelgrpc.StreamServerInterceptor())
 	}
-}
-
-func BasicUnaryServerInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
-		handler grpc.UnaryHandler) (resp interface{}, err error) {
-		switch request := req.(type) {
-		case *hello_proto.HelloRequest:
-			request.Name = "[MODIFIED BY SERVER INTERCEPTOR - 1]" + request.Name
-		}
-
-		responseMetadata, ok := metadata.FromOutgoingContext(ctx)
-
-		if !ok {
-			responseMetadata = metadata.New(nil)
-		}
-
-		responseMetadata.Append("my-response-metadata-key-1", "my-response-metadata-value-1")
-		responseMetadata.Append("my-response-metadata-key-2", "my-response-metadata-value-2")
-
-		ctx = metadata.NewOutgoingContext(ctx, responseMetadata)
-
-		grpc.SetHeader(ctx, responseMetadata)
-
-		// handle request with modified context
-		res, err := handler(ctx, req)
-
-		if err != nil {
-			return res, err
-		}
-
-		// modify response
-		switch response := res.(type) {
-		case *hello_proto.HelloResponse:
-			response.Greet = "[MODIFIED BY SERVER INTERCEPTOR - 2]" + response.Greet
-		case *resl_proto.ResiliencyResponse:
-			response.DummyString = "[MODIFIED BY SERVER INTERCEPTOR - 3]" + response.DummyString
-		}
-
-		return res, nil
-	}
-}
 
-func LogStreamServerInterceptor() grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
-		handler grpc.StreamHandler) error {
-		log.Println("[LOGGED BY SERVER INTERCEPTOR]", info)
-
-		return handler(srv, ss)
+	if cfg.Interceptors {
+		unaries = append(unaries, RequestIdUnaryServerInterceptor(), LogUnaryServerInterceptor())
+		streams = append(streams, RequestIdStreamServerInterceptor(), LogStreamServerInterceptor())
 	}
-}
-
-type InterceptedServerStream struct {
-	grpc.ServerStream
-}
-
-func BasicStreamServerInterceptor() grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
-		handler grpc.StreamHandler) error {
-		// intercept stream
-		interceptedServerStream := &InterceptedServerStream{
-			ServerStream: ss,
-		}
-
-		// add response metadata
-		responseMetadata, ok := metadata.FromOutgoingContext(interceptedServerStream.Context())
-
-		if !ok {
-			responseMetadata = metadata.New(nil)
-		}
 
-		responseMetadata.Append("my-response-metadata-key-1", "my-response-metadata-value-1")
-		responseMetadata.Append("my-response-metadata-key-2", "my-response-metadata-value-2")
-
-		interceptedServerStream.SetHeader(responseMetadata)
-
-		// handle request
-		return handler(srv, interceptedServerStream)
+	if cfg.Metrics {
+		unaries = append(unaries, MetricsUnaryServerInterceptor())
+		streams = append(streams, MetricsStreamServerInterceptor())
 	}
-}
 
-func (s *InterceptedServerStream) RecvMsg(msg interface{}) error {
-	if err := s.ServerStream.RecvMsg(msg); err != nil {
-		return err
+	if cfg.Interceptors {
+		unaries = append(unaries, RecoveryUnaryServerInterceptor())
+		streams = append(streams, RecoveryStreamServerInterceptor())
 	}
 
-	switch request := msg.(type) {
-	case *hello_proto.HelloRequest:
-		request.Name = "[MODIFIED BY SERVER INTERCEPTOR - 4]" + request.Name
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaries...),
+		grpc.ChainStreamInterceptor(streams...),
 	}
-
-	return nil
 }
 
-func (s *InterceptedServerStream) SendMsg(msg interface{}) error {
-	switch response := msg.(type) {
-	case *hello_proto.HelloResponse:
-		response.Greet = "[MODIFIED BY SERVER INTERCEPTOR - 5]" + response.Greet
-	case *resl_proto.ResiliencyResponse:
-		response.DummyString = "[MODIFIED BY SERVER INTERCEPTOR - 6]" + response.DummyString
-	}
+// contextServerStream is a server stream carrying a context replaced by an interceptor.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
 
-	return s.ServerStream.SendMsg(msg)
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
 }
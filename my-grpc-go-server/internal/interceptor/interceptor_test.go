@@ -0,0 +1,304 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// echoServer greets with the request id of the RPC, and panics when asked to.
+type echoServer struct {
+	hello.UnimplementedHelloServiceServer
+}
+
+func (s *echoServer) SayHello(ctx context.Context, req *hello.HelloRequest) (*hello.HelloResponse, error) {
+	if req.Name == "panic" {
+		panic("boom")
+	}
+
+	return &hello.HelloResponse{Greet: RequestIdFromContext(ctx)}, nil
+}
+
+func (s *echoServer) SayManyHellos(req *hello.HelloRequest, stream hello.HelloService_SayManyHellosServer) error {
+	if req.Name == "panic" {
+		panic("boom")
+	}
+
+	return stream.Send(&hello.HelloResponse{Greet: RequestIdFromContext(stream.Context())})
+}
+
+// syncBuffer collects the log output of the server goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// startInterceptedServer serves echoServer behind the whole server interceptor chain until the
+// test ends, and returns a client of it and the server log output.
+func startInterceptedServer(t *testing.T) (hello.HelloServiceClient, *syncBuffer) {
+	t.Helper()
+
+	return startServerWith(t, config.GrpcConfig{Interceptors: true, Metrics: true, Tracing: true})
+}
+
+// startServerWith is startInterceptedServer with the interceptors enabled by cfg.
+func startServerWith(t *testing.T, cfg config.GrpcConfig) (hello.HelloServiceClient, *syncBuffer) {
+	t.Helper()
+
+	logs := &syncBuffer{}
+	log.SetOutput(logs)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(ServerOptions(cfg)...)
+	hello.RegisterHelloServiceServer(server, &echoServer{})
+
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("Dial : %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		log.SetOutput(os.Stderr)
+	})
+
+	return hello.NewHelloServiceClient(conn), logs
+}
+
+func withRequestId(id string) context.Context {
+	if id == "" {
+		return context.Background()
+	}
+
+	return metadata.AppendToOutgoingContext(context.Background(), RequestIdHeader, id)
+}
+
+type requestIdTest struct {
+	name string
+	sent string
+	// kept is set when the server keeps the sent id, else it generates a uuid
+	kept bool
+}
+
+var requestIdTests = []requestIdTest{
+	{"missing", "", false},
+	{"valid", "req-0123456789", true},
+	{"longest", strings.Repeat("a", maxRequestIdLength), true},
+	{"too long", strings.Repeat("a", maxRequestIdLength+1), false},
+	{"space", "req 1", false},
+}
+
+func TestRequestIdValidation(t *testing.T) {
+	// sent by clients not checking header values, unlike grpc-go
+	tests := append([]requestIdTest{
+		{"control character", "req\x01", false},
+		{"non ascii", "req-é", false},
+	}, requestIdTests...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if tt.sent != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIdHeader, tt.sent))
+			}
+
+			id := requestId(ctx)
+			assertRequestId(t, tt.sent, tt.kept, metadata.Pairs(RequestIdHeader, id), id)
+		})
+	}
+}
+
+func assertRequestId(t *testing.T, sent string, kept bool, header metadata.MD, handlerId string) {
+	t.Helper()
+
+	ids := header.Get(RequestIdHeader)
+
+	if len(ids) != 1 {
+		t.Fatalf("%v response header = %v, want one id", RequestIdHeader, ids)
+	}
+
+	if ids[0] != handlerId {
+		t.Errorf("response header id %q, handler id %q, want the same", ids[0], handlerId)
+	}
+
+	if kept && ids[0] != sent {
+		t.Errorf("request id = %q, want %q", ids[0], sent)
+	}
+
+	if !kept {
+		if _, err := uuid.Parse(ids[0]); err != nil || ids[0] == sent {
+			t.Errorf("request id = %q, want a new uuid", ids[0])
+		}
+	}
+}
+
+func TestRequestIdUnary(t *testing.T) {
+	client, _ := startInterceptedServer(t)
+
+	for _, tt := range requestIdTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+
+			res, err := client.SayHello(withRequestId(tt.sent), &hello.HelloRequest{Name: "id"},
+				grpc.Header(&header))
+
+			if err != nil {
+				t.Fatalf("SayHello : %v", err)
+			}
+
+			assertRequestId(t, tt.sent, tt.kept, header, res.Greet)
+		})
+	}
+}
+
+func TestRequestIdStream(t *testing.T) {
+	client, _ := startInterceptedServer(t)
+
+	for _, tt := range requestIdTests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.SayManyHellos(withRequestId(tt.sent), &hello.HelloRequest{Name: "id"})
+
+			if err != nil {
+				t.Fatalf("SayManyHellos : %v", err)
+			}
+
+			res, err := stream.Recv()
+
+			if err != nil {
+				t.Fatalf("Recv : %v", err)
+			}
+
+			header, err := stream.Header()
+
+			if err != nil {
+				t.Fatalf("Header : %v", err)
+			}
+
+			assertRequestId(t, tt.sent, tt.kept, header, res.Greet)
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	client, logs := startInterceptedServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		call   func(ctx context.Context, name string) error
+	}{
+		{"unary", "/hello.HelloService/SayHello", func(ctx context.Context, name string) error {
+			_, err := client.SayHello(ctx, &hello.HelloRequest{Name: name})
+			return err
+		}},
+		{"stream", "/hello.HelloService/SayManyHellos", func(ctx context.Context, name string) error {
+			stream, err := client.SayManyHellos(ctx, &hello.HelloRequest{Name: name})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = stream.Recv()
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(withRequestId("panic-"+tt.name), "panic")
+
+			if st := status.Convert(err); st.Code() != codes.Internal || st.Message() != "internal server error" {
+				t.Errorf("panicking handler = %v, want Internal without the panic value", err)
+			}
+
+			// the server survived the panic
+			if err := tt.call(context.Background(), "again"); err != nil {
+				t.Errorf("call after panic : %v", err)
+			}
+
+			out := logs.String()
+
+			if !strings.Contains(out, "Panic on "+tt.method+" (request id panic-"+tt.name+") : boom") {
+				t.Errorf("panic not logged with its request id :\n%v", out)
+			}
+
+			if !strings.Contains(out, "method="+tt.method+" type="+tt.name+" ") ||
+				!strings.Contains(out, "request_id=panic-"+tt.name+" ") || !strings.Contains(out, "code=Internal") {
+				t.Errorf("recovered panic not logged as an Internal %v RPC :\n%v", tt.name, out)
+			}
+		})
+	}
+}
+
+func TestLogRequest(t *testing.T) {
+	client, logs := startInterceptedServer(t)
+
+	if _, err := client.SayHello(withRequestId("log-unary"), &hello.HelloRequest{Name: "log"}); err != nil {
+		t.Fatalf("SayHello : %v", err)
+	}
+
+	stream, err := client.SayManyHellos(withRequestId("log-stream"), &hello.HelloRequest{Name: "log"})
+
+	if err != nil {
+		t.Fatalf("SayManyHellos : %v", err)
+	}
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	if err != io.EOF {
+		t.Fatalf("Recv : %v", err)
+	}
+
+	for _, want := range []string{
+		"grpc method=/hello.HelloService/SayHello type=unary peer=bufconn request_id=log-unary " +
+			"trace_id=none code=OK duration=",
+		"grpc method=/hello.HelloService/SayManyHellos type=stream peer=bufconn request_id=log-stream " +
+			"trace_id=none code=OK duration=",
+	} {
+		if out := logs.String(); !strings.Contains(out, want) {
+			t.Errorf("log %q not found in :\n%v", want, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// LogUnaryServerInterceptor logs one key=value line per RPC once it is done.
+func LogUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		start := time.Now()
+
+		resp, err = handler(ctx, req)
+
+		logRequest(ctx, info.FullMethod, "unary", start, err)
+
+		return resp, err
+	}
+}
+
+// LogStreamServerInterceptor logs one key=value line per stream once it is closed.
+func LogStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+
+		logRequest(ss.Context(), info.FullMethod, "stream", start, err)
+
+		return err
+	}
+}
+
+func logRequest(ctx context.Context, method string, kind string, start time.Time, err error) {
+	peerAddr := "unknown"
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+
+	traceId := "none"
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		traceId = sc.TraceID().String()
+	}
+
+	st := status.Convert(err)
+
+	if err == nil {
+		log.Printf("grpc method=%v type=%v peer=%v request_id=%v trace_id=%v code=%v duration=%v\n",
+			method, kind, peerAddr, RequestIdFromContext(ctx), traceId, st.Code(), time.Since(start))
+		return
+	}
+
+	log.Printf("grpc method=%v type=%v peer=%v request_id=%v trace_id=%v code=%v duration=%v error=%q\n",
+		method, kind, peerAddr, RequestIdFromContext(ctx), traceId, st.Code(), time.Since(start), st.Message())
+}
@@ -0,0 +1,115 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	unary           = "unary"
+	clientStreaming = "client_stream"
+	serverStreaming = "server_stream"
+	bidiStreaming   = "bidi_stream"
+)
+
+// MetricsUnaryServerInterceptor counts unary RPCs and their handling time.
+func MetricsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		service, method := splitMethodName(info.FullMethod)
+		start := time.Now()
+
+		metrics.GrpcServerStarted.WithLabelValues(unary, service, method).Inc()
+		metrics.GrpcServerMsgReceived.WithLabelValues(unary, service, method).Inc()
+
+		resp, err = handler(ctx, req)
+
+		if err == nil {
+			metrics.GrpcServerMsgSent.WithLabelValues(unary, service, method).Inc()
+		}
+
+		metrics.GrpcServerHandled.WithLabelValues(unary, service, method, status.Code(err).String()).Inc()
+		metrics.GrpcServerHandlingSeconds.WithLabelValues(unary, service, method).
+			Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// MetricsStreamServerInterceptor counts streaming RPCs, their messages and their duration.
+func MetricsStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		service, method := splitMethodName(info.FullMethod)
+		kind := streamType(info)
+		start := time.Now()
+
+		metrics.GrpcServerStarted.WithLabelValues(kind, service, method).Inc()
+
+		err := handler(srv, &metricsServerStream{
+			ServerStream: ss,
+			received:     metrics.GrpcServerMsgReceived.WithLabelValues(kind, service, method),
+			sent:         metrics.GrpcServerMsgSent.WithLabelValues(kind, service, method),
+		})
+
+		metrics.GrpcServerHandled.WithLabelValues(kind, service, method, status.Code(err).String()).Inc()
+		metrics.GrpcServerHandlingSeconds.WithLabelValues(kind, service, method).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+type metricsServerStream struct {
+	grpc.ServerStream
+	received prometheus.Counter
+	sent     prometheus.Counter
+}
+
+func (s *metricsServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err == nil {
+		s.received.Inc()
+	}
+
+	return err
+}
+
+func (s *metricsServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err == nil {
+		s.sent.Inc()
+	}
+
+	return err
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return bidiStreaming
+	case info.IsClientStream:
+		return clientStreaming
+	default:
+		return serverStreaming
+	}
+}
+
+// splitMethodName splits /package.Service/Method into package.Service and Method.
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", fullMethod
+}
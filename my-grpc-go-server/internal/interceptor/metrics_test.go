@@ -0,0 +1,136 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+	"github.com/timpamungkas/my-grpc-proto/protogen/go/hello"
+)
+
+// rpcCounts are the metric values of a method, read before and after the RPCs of a test since
+// the metrics are global.
+type rpcCounts struct {
+	started  float64
+	handled  float64
+	received float64
+	sent     float64
+	observed uint64
+}
+
+func readRpcCounts(t *testing.T, kind string, method string, code string) rpcCounts {
+	t.Helper()
+
+	return rpcCounts{
+		started:  testutil.ToFloat64(metrics.GrpcServerStarted.WithLabelValues(kind, "hello.HelloService", method)),
+		handled:  testutil.ToFloat64(metrics.GrpcServerHandled.WithLabelValues(kind, "hello.HelloService", method, code)),
+		received: testutil.ToFloat64(metrics.GrpcServerMsgReceived.WithLabelValues(kind, "hello.HelloService", method)),
+		sent:     testutil.ToFloat64(metrics.GrpcServerMsgSent.WithLabelValues(kind, "hello.HelloService", method)),
+		observed: sampleCount(t, metrics.GrpcServerHandlingSeconds.WithLabelValues(kind, "hello.HelloService", method)),
+	}
+}
+
+// sampleCount returns the number of observations of histogram.
+func sampleCount(t *testing.T, histogram prometheus.Observer) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+
+	if err := histogram.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write : %v", err)
+	}
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func (c rpcCounts) sub(before rpcCounts) rpcCounts {
+	return rpcCounts{
+		started:  c.started - before.started,
+		handled:  c.handled - before.handled,
+		received: c.received - before.received,
+		sent:     c.sent - before.sent,
+		observed: c.observed - before.observed,
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		method string
+		code   string
+		call   func(client hello.HelloServiceClient) error
+		want   rpcCounts
+	}{
+		{"unary", unary, "SayHello", "OK", func(client hello.HelloServiceClient) error {
+			_, err := client.SayHello(context.Background(), &hello.HelloRequest{Name: "metrics"})
+			return err
+		}, rpcCounts{started: 1, handled: 1, received: 1, sent: 1, observed: 1}},
+		{"unary panic", unary, "SayHello", "Internal", func(client hello.HelloServiceClient) error {
+			client.SayHello(context.Background(), &hello.HelloRequest{Name: "panic"})
+			return nil
+		}, rpcCounts{started: 1, handled: 1, received: 1, observed: 1}},
+		{"server stream", serverStreaming, "SayManyHellos", "OK", func(client hello.HelloServiceClient) error {
+			stream, err := client.SayManyHellos(context.Background(), &hello.HelloRequest{Name: "metrics"})
+
+			if err != nil {
+				return err
+			}
+
+			for {
+				if _, err := stream.Recv(); err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+		}, rpcCounts{started: 1, handled: 1, received: 1, sent: 1, observed: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := startInterceptedServer(t)
+			before := readRpcCounts(t, tt.kind, tt.method, tt.code)
+
+			if err := tt.call(client); err != nil {
+				t.Fatalf("%v : %v", tt.method, err)
+			}
+
+			if got := readRpcCounts(t, tt.kind, tt.method, tt.code).sub(before); got != tt.want {
+				t.Errorf("metrics changed by %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsSwitch(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.GrpcConfig
+		want float64
+	}{
+		{"without other interceptors", config.GrpcConfig{Metrics: true}, 1},
+		{"disabled", config.GrpcConfig{Interceptors: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := startServerWith(t, tt.cfg)
+			started := metrics.GrpcServerStarted.WithLabelValues(unary, "hello.HelloService", "SayHello")
+			before := testutil.ToFloat64(started)
+
+			if _, err := client.SayHello(context.Background(), &hello.HelloRequest{Name: "metrics"}); err != nil {
+				t.Fatalf("SayHello : %v", err)
+			}
+
+			if got := testutil.ToFloat64(started) - before; got != tt.want {
+				t.Errorf("grpc_server_started_total changed by %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"github.com/timpamungkas/my-grpc-go-server/internal/metrics"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	rateLimitReasonRate    = "rate"
+	rateLimitReasonStreams = "streams"
+
+	// streamRetryDelay is suggested to clients over their stream limit, a stream slot frees up
+	// whenever one of their streams ends
+	streamRetryDelay = time.Second
+
+	// idle client limits are dropped after limiterIdleTimeout, checked every limiterSweepInterval
+	limiterIdleTimeout   = 10 * time.Minute
+	limiterSweepInterval = time.Minute
+
+	// forwardedForMetadata holds the client addresses a request went through, the REST gateway
+	// appends the address of its caller
+	forwardedForMetadata = "x-forwarded-for"
+)
+
+// unlimitedServices are never limited, load balancers and tools must always reach them.
+var unlimitedServices = map[string]bool{
+	"grpc.health.v1.Health":                    true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+	"grpc.reflection.v1.ServerReflection":      true,
+}
+
+// clientLimit is the token bucket and open stream count of a client on a method group.
+type clientLimit struct {
+	limiter  *rate.Limiter
+	streams  int
+	lastSeen time.Time
+}
+
+// RateLimiter limits the requests and concurrent streams of each client per method group,
+// following the rate limit rules. Clients are identified by their authenticated subject, else
+// their API key when it is a configured one, else their IP address, forwarded by trusted
+// proxies. Unknown API keys are ignored, a caller can't get a fresh limit by sending a new key
+// on every request.
+type RateLimiter struct {
+	rules        []config.RateLimitRule
+	apiKeyHeader string
+	// apiKeys are the ids of the configured API keys, by the hash of the key
+	apiKeys        map[[sha256.Size]byte]string
+	trustedProxies []*net.IPNet
+	mu             sync.Mutex
+	clients        map[string]*clientLimit
+	swept          time.Time
+}
+
+func NewRateLimiter(cfg config.RateLimitConfig) (*RateLimiter, error) {
+	rules, err := cfg.RateLimitRules()
+
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := cfg.TrustedProxyNets()
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys := make(map[[sha256.Size]byte]string, len(cfg.ApiKeys))
+
+	for _, k := range cfg.ApiKeys {
+		if k == "" {
+			continue
+		}
+
+		sum := sha256.Sum256([]byte(k))
+		apiKeys[sum] = "api_key:" + hex.EncodeToString(sum[:8])
+	}
+
+	return &RateLimiter{
+		rules:          rules,
+		apiKeyHeader:   strings.ToLower(cfg.ApiKeyHeader),
+		apiKeys:        apiKeys,
+		trustedProxies: trustedProxies,
+		clients:        map[string]*clientLimit{},
+		swept:          time.Now(),
+	}, nil
+}
+
+// RateLimitUnaryServerInterceptor refuses the requests of clients over their rate with
+// ResourceExhausted. It must run after authentication to limit clients by subject.
+func RateLimitUnaryServerInterceptor(l *RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if _, err := l.allow(ctx, info.FullMethod, false); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamServerInterceptor refuses the streams of clients over their rate or their
+// concurrent stream limit with ResourceExhausted. Messages on an open stream are not limited.
+func RateLimitStreamServerInterceptor(l *RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		release, err := l.allow(ss.Context(), info.FullMethod, true)
+
+		if err != nil {
+			return err
+		}
+
+		if release != nil {
+			defer release()
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// allow takes a token from the client bucket of fullMethod group, and a stream slot for a
+// stream. The token is not given back when the stream slot is refused, a client retrying in a
+// loop is limited by its rate too. The returned func frees the stream slot, it is nil when no
+// slot was taken.
+func (l *RateLimiter) allow(ctx context.Context, fullMethod string, stream bool) (func(), error) {
+	service, method := splitMethodName(fullMethod)
+
+	if unlimitedServices[service] {
+		return nil, nil
+	}
+
+	rule := l.rule(fullMethod)
+	key := l.clientKey(ctx)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	c := l.client(key, rule, now)
+
+	if r := c.limiter.ReserveN(now, 1); !r.OK() || r.DelayFrom(now) > 0 {
+		delay := r.DelayFrom(now)
+		r.CancelAt(now)
+
+		metrics.GrpcServerRateLimited.WithLabelValues(service, method, rateLimitReasonRate).Inc()
+
+		return nil, resourceExhausted(key, fmt.Sprintf("rate of %v requests per second (burst %v) exceeded on %v",
+			rule.Rate, rule.Burst, groupName(rule)), delay)
+	}
+
+	if !stream || rule.MaxStreams == 0 {
+		return nil, nil
+	}
+
+	if c.streams >= rule.MaxStreams {
+		metrics.GrpcServerRateLimited.WithLabelValues(service, method, rateLimitReasonStreams).Inc()
+
+		return nil, resourceExhausted(key, fmt.Sprintf("%v concurrent streams exceeded on %v",
+			rule.MaxStreams, groupName(rule)), streamRetryDelay)
+	}
+
+	c.streams++
+
+	// sweep keeps clients with open streams, c is still the limit of key when released
+	return func() { l.release(c) }, nil
+}
+
+// release frees a stream slot of c taken by allow.
+func (l *RateLimiter) release(c *clientLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c.streams > 0 {
+		c.streams--
+		c.lastSeen = time.Now()
+	}
+}
+
+// rule returns the rule of the method, else of its service, else the default one.
+func (l *RateLimiter) rule(fullMethod string) config.RateLimitRule {
+	service := fullMethod[:strings.LastIndex(fullMethod, "/")+1]
+
+	for _, r := range l.rules {
+		if r.Selector == fullMethod {
+			return r
+		}
+	}
+
+	for _, r := range l.rules {
+		if r.Selector == service {
+			return r
+		}
+	}
+
+	return l.rules[len(l.rules)-1]
+}
+
+// client returns the limits of key on the rule group, it must be called with the lock held.
+func (l *RateLimiter) client(key string, rule config.RateLimitRule, now time.Time) *clientLimit {
+	id := key + " " + rule.Selector
+	c, found := l.clients[id]
+
+	if !found {
+		c = &clientLimit{limiter: rate.NewLimiter(rate.Limit(rule.Rate), rule.Burst)}
+		l.clients[id] = c
+	}
+
+	c.lastSeen = now
+
+	return c
+}
+
+// sweep drops the limits of clients idle for a while, with no open stream. It must be called
+// with the lock held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < limiterSweepInterval {
+		return
+	}
+
+	l.swept = now
+
+	for id, c := range l.clients {
+		if c.streams == 0 && now.Sub(c.lastSeen) > limiterIdleTimeout {
+			delete(l.clients, id)
+		}
+	}
+}
+
+// clientKey identifies the caller : its authenticated subject, else a hash of its API key when
+// it is a configured one, so the key doesn't end up in logs and error details, else its IP
+// address.
+func (l *RateLimiter) clientKey(ctx context.Context) string {
+	if p, ok := dauth.FromContext(ctx); ok {
+		return "subject:" + p.Subject
+	}
+
+	if l.apiKeyHeader != "" && len(l.apiKeys) > 0 {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(l.apiKeyHeader); len(values) > 0 && values[0] != "" {
+				if id, found := l.apiKeys[sha256.Sum256([]byte(values[0]))]; found {
+					return id
+				}
+			}
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+
+		if err != nil {
+			host = p.Addr.String()
+		}
+
+		return "ip:" + l.forwardedFor(ctx, host)
+	}
+
+	return "unknown"
+}
+
+// forwardedFor returns the address of the client calling through the peer at addr : when the
+// peer is a trusted proxy, the last x-forwarded-for address not of a trusted proxy, else addr.
+// Addresses before it are set by the client and may be forged, they are never used.
+func (l *RateLimiter) forwardedFor(ctx context.Context, addr string) string {
+	if !l.trustedProxy(addr) {
+		return addr
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	hops := strings.Split(strings.Join(md.Get(forwardedForMetadata), ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+
+		if ip == nil {
+			return addr
+		}
+
+		addr = ip.String()
+
+		if !l.trustedProxy(addr) {
+			return addr
+		}
+	}
+
+	return addr
+}
+
+func (l *RateLimiter) trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+
+	for _, n := range l.trustedProxies {
+		if ip != nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func groupName(rule config.RateLimitRule) string {
+	if rule.Selector == "" {
+		return "default limit"
+	}
+
+	return strings.Trim(rule.Selector, "/")
+}
+
+func resourceExhausted(key string, description string, retryDelay time.Duration) error {
+	log.Printf("Rate limited %v : %v\n", key, description)
+
+	if retryDelay < time.Millisecond {
+		retryDelay = time.Millisecond
+	}
+
+	s := status.New(codes.ResourceExhausted, description)
+	s, _ = s.WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{
+				{
+					Subject:     key,
+					Description: description,
+				},
+			},
+		},
+		&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(retryDelay.Round(time.Millisecond)),
+		},
+	)
+
+	return s.Err()
+}
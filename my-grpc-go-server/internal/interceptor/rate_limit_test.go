@@ -0,0 +1,240 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+	"github.com/timpamungkas/my-grpc-go-server/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newTestRateLimiter(t *testing.T, cfg config.RateLimitConfig) *RateLimiter {
+	t.Helper()
+
+	l, err := NewRateLimiter(cfg)
+
+	if err != nil {
+		t.Fatalf("NewRateLimiter : %v", err)
+	}
+
+	return l
+}
+
+func clientContext(ip string, apiKey string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000},
+	})
+
+	if apiKey != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", apiKey))
+	}
+
+	return ctx
+}
+
+func TestClientKey(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{
+		ApiKeyHeader: "X-Api-Key",
+		ApiKeys:      []string{"known-key"},
+		Rate:         1,
+		Burst:        1,
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no api key", clientContext("10.0.0.1", ""), "ip:10.0.0.1"},
+		{"unknown api key", clientContext("10.0.0.1", "random-key"), "ip:10.0.0.1"},
+		{"known api key", clientContext("10.0.0.1", "known-key"), "api_key:"},
+		{"subject", dauth.NewContext(clientContext("10.0.0.1", "known-key"),
+			dauth.Principal{Subject: "alice"}), "subject:alice"},
+		{"no peer", context.Background(), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.clientKey(tt.ctx); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("clientKey = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := l.clientKey(clientContext("10.0.0.1", "known-key")); strings.Contains(got, "known-key") {
+		t.Errorf("clientKey %q leaks the API key", got)
+	}
+}
+
+// gatewayContext is the context of a request the REST gateway at gatewayIp forwards for the
+// clients of forwardedFor, as grpc-gateway sets x-forwarded-for.
+func gatewayContext(gatewayIp string, forwardedFor string) context.Context {
+	ctx := clientContext(gatewayIp, "")
+
+	if forwardedFor != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", forwardedFor))
+	}
+
+	return ctx
+}
+
+func TestClientKeyBehindGateway(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{
+		TrustedProxies: []string{"10.0.1.0/24", "::1"},
+		Rate:           1,
+		Burst:          1,
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"forwarded client", gatewayContext("10.0.1.5", "203.0.113.7"), "ip:203.0.113.7"},
+		{"forged hops", gatewayContext("10.0.1.5", "198.51.100.1, 203.0.113.7"), "ip:203.0.113.7"},
+		{"trusted hops", gatewayContext("10.0.1.5", "203.0.113.7, 10.0.1.9"), "ip:203.0.113.7"},
+		{"ipv6 gateway", gatewayContext("::1", "2001:db8::7"), "ip:2001:db8::7"},
+		{"no forwarded client", gatewayContext("10.0.1.5", ""), "ip:10.0.1.5"},
+		{"invalid forwarded client", gatewayContext("10.0.1.5", "unknown"), "ip:10.0.1.5"},
+		{"untrusted peer", gatewayContext("10.0.2.5", "203.0.113.7"), "ip:10.0.2.5"},
+		{"subject", dauth.NewContext(gatewayContext("10.0.1.5", "203.0.113.7"),
+			dauth.Principal{Subject: "alice"}), "subject:alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.clientKey(tt.ctx); got != tt.want {
+				t.Errorf("clientKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowBehindGateway(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{
+		TrustedProxies: []string{"10.0.1.5"},
+		Rate:           0.001,
+		Burst:          2,
+		MaxStreams:     1,
+	})
+
+	// every client of the gateway has its own stream slot and bucket
+	for _, client := range []string{"203.0.113.1", "203.0.113.2"} {
+		if _, err := l.allow(gatewayContext("10.0.1.5", client), "/bank.BankService/FetchExchangeRates",
+			true); err != nil {
+			t.Fatalf("stream of %v : %v", client, err)
+		}
+	}
+
+	_, err := l.allow(gatewayContext("10.0.1.5", "203.0.113.1"), "/bank.BankService/FetchExchangeRates", true)
+
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second stream = %v, want ResourceExhausted", err)
+	}
+
+	if _, err := l.allow(gatewayContext("10.0.1.5", "203.0.113.2"), "/bank.BankService/GetCurrentBalance",
+		false); err != nil {
+		t.Fatalf("call of the other client : %v", err)
+	}
+}
+
+func TestClientKeyWithoutConfiguredKeys(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{ApiKeyHeader: "x-api-key", Rate: 1, Burst: 1})
+
+	if got := l.clientKey(clientContext("10.0.0.2", "any-key")); got != "ip:10.0.0.2" {
+		t.Errorf("clientKey = %q, want ip:10.0.0.2", got)
+	}
+}
+
+func TestAllowIgnoresUnknownApiKeys(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{
+		ApiKeyHeader: "x-api-key",
+		ApiKeys:      []string{"known-key"},
+		Rate:         0.001,
+		Burst:        2,
+	})
+
+	// a new random key on every call still shares the bucket of the caller IP
+	for i := 0; i < 2; i++ {
+		if _, err := l.allow(clientContext("10.0.0.3", fmt.Sprintf("key-%d", i)),
+			"/bank.BankService/GetCurrentBalance", false); err != nil {
+			t.Fatalf("call %d : %v", i, err)
+		}
+	}
+
+	_, err := l.allow(clientContext("10.0.0.3", "key-2"), "/bank.BankService/GetCurrentBalance", false)
+
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("third call = %v, want ResourceExhausted", err)
+	}
+
+	// a configured key has its own bucket
+	if _, err := l.allow(clientContext("10.0.0.3", "known-key"), "/bank.BankService/GetCurrentBalance",
+		false); err != nil {
+		t.Fatalf("known key : %v", err)
+	}
+}
+
+func TestStreamSlotsOfUnlimitedServices(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{Rate: 1000, Burst: 1000, MaxStreams: 1})
+	interceptor := RateLimitStreamServerInterceptor(l)
+	ss := &contextServerStream{ctx: clientContext("10.0.0.4", "")}
+
+	openStream := func(fullMethod string, handler grpc.StreamHandler) error {
+		return interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: fullMethod}, handler)
+	}
+
+	done := make(chan struct{})
+	opened := make(chan struct{})
+
+	go func() {
+		_ = openStream("/bank.BankService/TransferMultiple", func(interface{}, grpc.ServerStream) error {
+			close(opened)
+			<-done
+			return nil
+		})
+	}()
+
+	<-opened
+	defer close(done)
+
+	// finished health streams must not free the slot of the open BankService stream
+	for i := 0; i < 3; i++ {
+		if err := openStream("/grpc.health.v1.Health/Watch", func(interface{}, grpc.ServerStream) error {
+			return nil
+		}); err != nil {
+			t.Fatalf("health stream %d : %v", i, err)
+		}
+	}
+
+	err := openStream("/bank.BankService/SummarizeTransactions", func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second BankService stream = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestStreamSlotReleased(t *testing.T) {
+	l := newTestRateLimiter(t, config.RateLimitConfig{Rate: 1000, Burst: 1000, MaxStreams: 1})
+	interceptor := RateLimitStreamServerInterceptor(l)
+	ss := &contextServerStream{ctx: clientContext("10.0.0.5", "")}
+	info := &grpc.StreamServerInfo{FullMethod: "/bank.BankService/TransferMultiple"}
+
+	for i := 0; i < 3; i++ {
+		if err := interceptor(nil, ss, info, func(interface{}, grpc.ServerStream) error {
+			return nil
+		}); err != nil {
+			t.Fatalf("stream %d : %v", i, err)
+		}
+	}
+}
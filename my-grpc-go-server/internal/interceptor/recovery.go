@@ -0,0 +1,46 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryServerInterceptor turns a panic in the handler into an Internal error, instead
+// of crashing the server. The panic value and stack are only logged, never sent to the client.
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor turns a panic in the stream handler into an Internal error.
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(ss.Context(), info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recoveredError(ctx context.Context, method string, r interface{}) error {
+	log.Printf("Panic on %v (request id %v) : %v\n%s", method, RequestIdFromContext(ctx), r, debug.Stack())
+
+	return status.Error(codes.Internal, "internal server error")
+}
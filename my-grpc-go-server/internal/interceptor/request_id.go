@@ -0,0 +1,85 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIdHeader is the metadata key carrying the request id, in requests and response
+// headers. The REST gateway forwards the X-Request-Id HTTP header under this key.
+const RequestIdHeader = "x-request-id"
+
+const maxRequestIdLength = 128
+
+type requestIdKey struct{}
+
+// RequestIdFromContext returns the request id set by the request id interceptor, or an empty
+// string outside of an intercepted RPC.
+func RequestIdFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+
+	return id
+}
+
+// requestId returns the request id sent by the client, or a new one when it is missing or not
+// a short printable string.
+func requestId(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIdHeader); len(ids) > 0 && isValidRequestId(ids[0]) {
+			return ids[0]
+		}
+	}
+
+	return uuid.NewString()
+}
+
+func isValidRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestIdUnaryServerInterceptor puts the request id in the handler context and sends it back
+// in the response headers.
+func RequestIdUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		id := requestId(ctx)
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIdHeader, id)); err != nil {
+			log.Printf("Can't set %v response header : %v\n", RequestIdHeader, err)
+		}
+
+		return handler(context.WithValue(ctx, requestIdKey{}, id), req)
+	}
+}
+
+// RequestIdStreamServerInterceptor puts the request id in the stream context and sends it back
+// in the response headers.
+func RequestIdStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		id := requestId(ss.Context())
+
+		if err := ss.SetHeader(metadata.Pairs(RequestIdHeader, id)); err != nil {
+			log.Printf("Can't set %v response header : %v\n", RequestIdHeader, err)
+		}
+
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), requestIdKey{}, id),
+		})
+	}
+}
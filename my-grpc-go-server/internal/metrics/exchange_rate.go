@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type currencyPair struct {
+	fromCurrency string
+	toCurrency   string
+}
+
+// exchangeRateAgeCollector reports the age of the latest exchange rate of each currency pair,
+// computed at scrape time so a stalled provider shows up as a growing age.
+type exchangeRateAgeCollector struct {
+	mu        sync.Mutex
+	validFrom map[currencyPair]time.Time
+	desc      *prometheus.Desc
+}
+
+var exchangeRateAge = &exchangeRateAgeCollector{
+	validFrom: map[currencyPair]time.Time{},
+	desc: prometheus.NewDesc("bank_exchange_rate_age_seconds",
+		"Time since the latest exchange rate of the currency pair became valid.",
+		[]string{"from_currency", "to_currency"}, nil),
+}
+
+func init() {
+	prometheus.MustRegister(exchangeRateAge)
+}
+
+// ExchangeRateUpdated records the latest exchange rate of the currency pair, valid from
+// validFrom.
+func ExchangeRateUpdated(fromCur string, toCur string, validFrom time.Time) {
+	exchangeRateAge.mu.Lock()
+	defer exchangeRateAge.mu.Unlock()
+
+	exchangeRateAge.validFrom[currencyPair{fromCurrency: fromCur, toCurrency: toCur}] = validFrom
+}
+
+func (c *exchangeRateAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *exchangeRateAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for pair, validFrom := range c.validFrom {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue,
+			time.Since(validFrom).Seconds(), pair.fromCurrency, pair.toCurrency)
+	}
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const MetricsPath = "/metrics"
+
+var (
+	GrpcServerStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_started_total",
+		Help: "RPCs started on the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	GrpcServerHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed on the server, by status code.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+
+	GrpcServerHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "RPC handling time on the server, up to the end of the stream for streaming RPCs.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	GrpcServerMsgReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_msg_received_total",
+		Help: "Messages received by the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	GrpcServerMsgSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_msg_sent_total",
+		Help: "Messages sent by the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	GrpcServerRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_rate_limited_total",
+		Help: "RPCs refused with ResourceExhausted, by reason (rate or streams).",
+	}, []string{"grpc_service", "grpc_method", "reason"})
+
+	DbQuerySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query time, by GORM operation and table.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Failed database queries, by GORM operation and table. Record not found is not an error.",
+	}, []string{"operation", "table"})
+
+	BankTransfers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bank_transfers_total",
+		Help: "Transfers requested, by result (success, failed or error) and error type.",
+	}, []string{"result", "error_type"})
+)
+
+// Run serves the metrics on address until ctx is done.
+func Run(ctx context.Context, address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.Handler())
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		log.Printf("Metrics listening on %v%v\n", address, MetricsPath)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve metrics on %v : %v", address, err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
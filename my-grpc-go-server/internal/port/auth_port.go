@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
+)
+
+type AuthenticatorPort interface {
+	Authenticate(ctx context.Context) (dauth.Principal, error)
+}
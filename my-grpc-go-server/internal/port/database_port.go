@@ -1,24 +1,43 @@
 package port
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
 	db "github.com/timpamungkas/my-grpc-go-server/internal/adapter/database"
+	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
 )
 
 type DummyDatabasePort interface {
-	Save(data *db.DummyOrm) (uuid.UUID, error)
-	GetByUuid(uuid *uuid.UUID) (db.DummyOrm, error)
+	Save(ctx context.Context, data *db.DummyOrm) (uuid.UUID, error)
+	GetByUuid(ctx context.Context, uuid *uuid.UUID) (db.DummyOrm, error)
 }
 
+// BankDatabasePort queries run under ctx : they are cancelled with it, releasing their
+// connection.
 type BankDatabasePort interface {
-	GetBankAccountByAccountNumber(acct string) (db.BankAccountOrm, error)
-	CreateExchangeRate(r db.BankExchangeRateOrm) (uuid.UUID, error)
-	GetExchangeRateAtTimestamp(fromCur string, toCur string, ts time.Time) (db.BankExchangeRateOrm, error)
-	CreateTransaction(acct db.BankAccountOrm, t db.BankTransactionOrm) (uuid.UUID, error)
-	CreateTransfer(transfer db.BankTransferOrm) (uuid.UUID, error)
-	CreateTransferTransactionPair(fromAccountOrm db.BankAccountOrm, toAccountOrm db.BankAccountOrm,
-		fromTransactionOrm db.BankTransactionOrm, toTransactionOrm db.BankTransactionOrm) (bool, error)
-	UpdateTransferStatus(transfer db.BankTransferOrm, status bool) error
+	GetBankAccountByAccountNumber(ctx context.Context, acct string) (db.BankAccountOrm, error)
+	IsAccountNumberExist(ctx context.Context, acct string) (bool, error)
+	UpdateAccountStatus(ctx context.Context,
+		h db.BankAccountStatusHistoryOrm) (db.BankAccountStatusHistoryOrm, error)
+	GetAccountPolicy(ctx context.Context, accountUuid uuid.UUID) (db.BankAccountPolicyOrm, bool, error)
+	SaveAccountPolicy(ctx context.Context, p db.BankAccountPolicyOrm) (db.BankAccountPolicyOrm, error)
+	CreateAccount(ctx context.Context, acct db.BankAccountOrm,
+		initialDeposit *db.BankTransactionOrm) (uuid.UUID, error)
+	CreateExchangeRate(ctx context.Context, r db.BankExchangeRateOrm) (uuid.UUID, error)
+	GetExchangeRateAtTimestamp(ctx context.Context, fromCur string, toCur string,
+		ts time.Time) (db.BankExchangeRateOrm, error)
+	CreateTransaction(ctx context.Context, acct db.BankAccountOrm, t db.BankTransactionOrm,
+		periods dbank.OutgoingPeriods) (uuid.UUID, error)
+	GetTransactionByIdempotencyKey(ctx context.Context, key string) (db.BankTransactionOrm, bool, error)
+	ListTransactions(ctx context.Context, f db.BankTransactionFilter) ([]db.BankTransactionOrm, error)
+	CreateTransfer(ctx context.Context, transfer db.BankTransferOrm) (uuid.UUID, error)
+	GetTransferByIdempotencyKey(ctx context.Context, key string) (db.BankTransferOrm, bool, error)
+	CreateTransferTransactionPair(ctx context.Context, fromAccountOrm db.BankAccountOrm,
+		toAccountOrm db.BankAccountOrm, fromTransactionOrm db.BankTransactionOrm,
+		toTransactionOrm db.BankTransactionOrm, periods dbank.OutgoingPeriods) (bool, error)
+	UpdateTransferStatus(ctx context.Context, transfer db.BankTransferOrm, status bool) error
+	RunInTransaction(ctx context.Context, fn func(uow db.BankUnitOfWork) error) error
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+type ExchangeRateProviderPort interface {
+	FetchExchangeRate(ctx context.Context, fromCur string, toCur string) (decimal.Decimal, error)
+}
@@ -1,25 +1,47 @@
 package port
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	dauth "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/auth"
 	dbank "github.com/timpamungkas/my-grpc-go-server/internal/application/domain/bank"
 )
 
 type HelloServicePort interface {
-	GenerateHello(name string) string
+	GenerateHello(ctx context.Context, name string) string
 }
 
+// HealthCheckPort returns an error when a service can't serve requests, e.g. its database is
+// unreachable.
+type HealthCheckPort interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// BankServicePort calls stop their database work once ctx is done.
 type BankServicePort interface {
-	FindCurrentBalance(acct string) (float64, error)
-	CreateExchangeRate(r dbank.ExchangeRate) (uuid.UUID, error)
-	FindExchangeRate(fromCur string, toCur string, ts time.Time) (float64, error)
-	CreateTransaction(acct string, t dbank.Transaction) (uuid.UUID, error)
-	CalculateTransactionSummary(tcur *dbank.TransactionSummary, trans dbank.Transaction) error
-	Transfer(tt dbank.TransferTransaction) (uuid.UUID, bool, error)
+	HealthCheckPort
+	FindCurrentBalance(ctx context.Context, acct string) (decimal.Decimal, string, error)
+	FindAccountLocation(ctx context.Context, acct string) (*time.Location, error)
+	CreateAccount(ctx context.Context, a dbank.Account) (uuid.UUID, string, error)
+	ChangeAccountStatus(ctx context.Context, c dbank.AccountStatusChange) (dbank.AccountStatusChangeResult, error)
+	FindAccountPolicy(ctx context.Context, acct string) (dbank.AccountPolicy, error)
+	UpdateAccountPolicy(ctx context.Context, p dbank.AccountPolicy) (dbank.AccountPolicy, error)
+	CreateExchangeRate(ctx context.Context, r dbank.ExchangeRate) (uuid.UUID, error)
+	FindExchangeRate(ctx context.Context, fromCur string, toCur string, ts time.Time) (decimal.Decimal, error)
+	SubscribeExchangeRates(ctx context.Context, fromCur string, toCur string) (<-chan dbank.ExchangeRate,
+		func(), error)
+	CreateTransaction(ctx context.Context, acct string, t dbank.Transaction) (uuid.UUID, error)
+	CalculateTransactionSummary(ctx context.Context, tcur *dbank.TransactionSummary, trans dbank.Transaction) error
+	ListTransactions(ctx context.Context, q dbank.TransactionQuery) (dbank.TransactionPage, error)
+	Transfer(ctx context.Context, tt dbank.TransferTransaction) (dbank.TransferResult, error)
+	AuthorizeAccount(ctx context.Context, p dauth.Principal, acct string) error
 }
 
 type ResiliencyServicePort interface {
-	GenerateResiliency(minDelaySecond int32, maxDelaySecond int32, statusCodes []uint32) (string, uint32)
+	// GenerateResiliency returns ctx error when ctx is done during the delay
+	GenerateResiliency(ctx context.Context, minDelaySecond int32, maxDelaySecond int32,
+		statusCodes []uint32) (string, uint32, error)
 }
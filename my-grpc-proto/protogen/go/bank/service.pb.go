@@ -32,7 +32,7 @@ var file_proto_bank_service_proto_rawDesc = []byte{
 	0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x1a, 0x1e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x74,
 	0x79, 0x70, 0x65, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x32, 0x8f, 0x03, 0x0a, 0x0b, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x74, 0x6f, 0x32, 0xec, 0x05, 0x0a, 0x0b, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x12, 0x50, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
 	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x43,
 	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
@@ -57,41 +57,78 @@ var file_proto_bank_service_proto_rawDesc = []byte{
 	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x43, 0x72, 0x65,
 	0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6d, 0x70, 0x61, 0x6d, 0x75, 0x6e, 0x67, 0x6b, 0x61, 0x73, 0x2f,
-	0x6d, 0x79, 0x2d, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x53, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5c, 0x0a, 0x13, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x20, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1d, 0x2e, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x13, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x12, 0x20, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x74, 0x69, 0x6d, 0x70, 0x61, 0x6d, 0x75, 0x6e, 0x67, 0x6b, 0x61, 0x73, 0x2f, 0x6d, 0x79, 0x2d,
+	0x67, 0x72, 0x70, 0x63, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var file_proto_bank_service_proto_goTypes = []interface{}{
-	(*CurrentBalanceRequest)(nil),  // 0: bank.CurrentBalanceRequest
-	(*ExchangeRateRequest)(nil),    // 1: bank.ExchangeRateRequest
-	(*Transaction)(nil),            // 2: bank.Transaction
-	(*TransferRequest)(nil),        // 3: bank.TransferRequest
-	(*CreateAccountRequest)(nil),   // 4: bank.CreateAccountRequest
-	(*CurrentBalanceResponse)(nil), // 5: bank.CurrentBalanceResponse
-	(*ExchangeRateResponse)(nil),   // 6: bank.ExchangeRateResponse
-	(*TransactionSummary)(nil),     // 7: bank.TransactionSummary
-	(*TransferResponse)(nil),       // 8: bank.TransferResponse
-	(*CreateAccountResponse)(nil),  // 9: bank.CreateAccountResponse
+	(*CurrentBalanceRequest)(nil),       // 0: bank.CurrentBalanceRequest
+	(*ExchangeRateRequest)(nil),         // 1: bank.ExchangeRateRequest
+	(*Transaction)(nil),                 // 2: bank.Transaction
+	(*TransferRequest)(nil),             // 3: bank.TransferRequest
+	(*CreateAccountRequest)(nil),        // 4: bank.CreateAccountRequest
+	(*ListTransactionsRequest)(nil),     // 5: bank.ListTransactionsRequest
+	(*UpdateAccountStatusRequest)(nil),  // 6: bank.UpdateAccountStatusRequest
+	(*GetAccountPolicyRequest)(nil),     // 7: bank.GetAccountPolicyRequest
+	(*UpdateAccountPolicyRequest)(nil),  // 8: bank.UpdateAccountPolicyRequest
+	(*CurrentBalanceResponse)(nil),      // 9: bank.CurrentBalanceResponse
+	(*ExchangeRateResponse)(nil),        // 10: bank.ExchangeRateResponse
+	(*TransactionSummary)(nil),          // 11: bank.TransactionSummary
+	(*TransferResponse)(nil),            // 12: bank.TransferResponse
+	(*CreateAccountResponse)(nil),       // 13: bank.CreateAccountResponse
+	(*ListTransactionsResponse)(nil),    // 14: bank.ListTransactionsResponse
+	(*UpdateAccountStatusResponse)(nil), // 15: bank.UpdateAccountStatusResponse
+	(*AccountPolicyResponse)(nil),       // 16: bank.AccountPolicyResponse
 }
 var file_proto_bank_service_proto_depIdxs = []int32{
-	0, // 0: bank.BankService.GetCurrentBalance:input_type -> bank.CurrentBalanceRequest
-	1, // 1: bank.BankService.FetchExchangeRates:input_type -> bank.ExchangeRateRequest
-	2, // 2: bank.BankService.SummarizeTransactions:input_type -> bank.Transaction
-	3, // 3: bank.BankService.TransferMultiple:input_type -> bank.TransferRequest
-	4, // 4: bank.BankService.CreateAccount:input_type -> bank.CreateAccountRequest
-	5, // 5: bank.BankService.GetCurrentBalance:output_type -> bank.CurrentBalanceResponse
-	6, // 6: bank.BankService.FetchExchangeRates:output_type -> bank.ExchangeRateResponse
-	7, // 7: bank.BankService.SummarizeTransactions:output_type -> bank.TransactionSummary
-	8, // 8: bank.BankService.TransferMultiple:output_type -> bank.TransferResponse
-	9, // 9: bank.BankService.CreateAccount:output_type -> bank.CreateAccountResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: bank.BankService.GetCurrentBalance:input_type -> bank.CurrentBalanceRequest
+	1,  // 1: bank.BankService.FetchExchangeRates:input_type -> bank.ExchangeRateRequest
+	2,  // 2: bank.BankService.SummarizeTransactions:input_type -> bank.Transaction
+	3,  // 3: bank.BankService.TransferMultiple:input_type -> bank.TransferRequest
+	4,  // 4: bank.BankService.CreateAccount:input_type -> bank.CreateAccountRequest
+	5,  // 5: bank.BankService.ListTransactions:input_type -> bank.ListTransactionsRequest
+	6,  // 6: bank.BankService.UpdateAccountStatus:input_type -> bank.UpdateAccountStatusRequest
+	7,  // 7: bank.BankService.GetAccountPolicy:input_type -> bank.GetAccountPolicyRequest
+	8,  // 8: bank.BankService.UpdateAccountPolicy:input_type -> bank.UpdateAccountPolicyRequest
+	9,  // 9: bank.BankService.GetCurrentBalance:output_type -> bank.CurrentBalanceResponse
+	10, // 10: bank.BankService.FetchExchangeRates:output_type -> bank.ExchangeRateResponse
+	11, // 11: bank.BankService.SummarizeTransactions:output_type -> bank.TransactionSummary
+	12, // 12: bank.BankService.TransferMultiple:output_type -> bank.TransferResponse
+	13, // 13: bank.BankService.CreateAccount:output_type -> bank.CreateAccountResponse
+	14, // 14: bank.BankService.ListTransactions:output_type -> bank.ListTransactionsResponse
+	15, // 15: bank.BankService.UpdateAccountStatus:output_type -> bank.UpdateAccountStatusResponse
+	16, // 16: bank.BankService.GetAccountPolicy:output_type -> bank.AccountPolicyResponse
+	16, // 17: bank.BankService.UpdateAccountPolicy:output_type -> bank.AccountPolicyResponse
+	9,  // [9:18] is the sub-list for method output_type
+	0,  // [0:9] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_proto_bank_service_proto_init() }
@@ -24,6 +24,10 @@ const (
 	BankService_SummarizeTransactions_FullMethodName = "/bank.BankService/SummarizeTransactions"
 	BankService_TransferMultiple_FullMethodName      = "/bank.BankService/TransferMultiple"
 	BankService_CreateAccount_FullMethodName         = "/bank.BankService/CreateAccount"
+	BankService_ListTransactions_FullMethodName      = "/bank.BankService/ListTransactions"
+	BankService_UpdateAccountStatus_FullMethodName   = "/bank.BankService/UpdateAccountStatus"
+	BankService_GetAccountPolicy_FullMethodName      = "/bank.BankService/GetAccountPolicy"
+	BankService_UpdateAccountPolicy_FullMethodName   = "/bank.BankService/UpdateAccountPolicy"
 )
 
 // BankServiceClient is the client API for BankService service.
@@ -35,6 +39,10 @@ type BankServiceClient interface {
 	SummarizeTransactions(ctx context.Context, opts ...grpc.CallOption) (BankService_SummarizeTransactionsClient, error)
 	TransferMultiple(ctx context.Context, opts ...grpc.CallOption) (BankService_TransferMultipleClient, error)
 	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	UpdateAccountStatus(ctx context.Context, in *UpdateAccountStatusRequest, opts ...grpc.CallOption) (*UpdateAccountStatusResponse, error)
+	GetAccountPolicy(ctx context.Context, in *GetAccountPolicyRequest, opts ...grpc.CallOption) (*AccountPolicyResponse, error)
+	UpdateAccountPolicy(ctx context.Context, in *UpdateAccountPolicyRequest, opts ...grpc.CallOption) (*AccountPolicyResponse, error)
 }
 
 type bankServiceClient struct {
@@ -160,6 +168,42 @@ func (c *bankServiceClient) CreateAccount(ctx context.Context, in *CreateAccount
 	return out, nil
 }
 
+func (c *bankServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, BankService_ListTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) UpdateAccountStatus(ctx context.Context, in *UpdateAccountStatusRequest, opts ...grpc.CallOption) (*UpdateAccountStatusResponse, error) {
+	out := new(UpdateAccountStatusResponse)
+	err := c.cc.Invoke(ctx, BankService_UpdateAccountStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) GetAccountPolicy(ctx context.Context, in *GetAccountPolicyRequest, opts ...grpc.CallOption) (*AccountPolicyResponse, error) {
+	out := new(AccountPolicyResponse)
+	err := c.cc.Invoke(ctx, BankService_GetAccountPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankServiceClient) UpdateAccountPolicy(ctx context.Context, in *UpdateAccountPolicyRequest, opts ...grpc.CallOption) (*AccountPolicyResponse, error) {
+	out := new(AccountPolicyResponse)
+	err := c.cc.Invoke(ctx, BankService_UpdateAccountPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BankServiceServer is the server API for BankService service.
 // All implementations must embed UnimplementedBankServiceServer
 // for forward compatibility
@@ -169,6 +213,10 @@ type BankServiceServer interface {
 	SummarizeTransactions(BankService_SummarizeTransactionsServer) error
 	TransferMultiple(BankService_TransferMultipleServer) error
 	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	UpdateAccountStatus(context.Context, *UpdateAccountStatusRequest) (*UpdateAccountStatusResponse, error)
+	GetAccountPolicy(context.Context, *GetAccountPolicyRequest) (*AccountPolicyResponse, error)
+	UpdateAccountPolicy(context.Context, *UpdateAccountPolicyRequest) (*AccountPolicyResponse, error)
 	mustEmbedUnimplementedBankServiceServer()
 }
 
@@ -191,6 +239,18 @@ func (UnimplementedBankServiceServer) TransferMultiple(BankService_TransferMulti
 func (UnimplementedBankServiceServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
 }
+func (UnimplementedBankServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedBankServiceServer) UpdateAccountStatus(context.Context, *UpdateAccountStatusRequest) (*UpdateAccountStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAccountStatus not implemented")
+}
+func (UnimplementedBankServiceServer) GetAccountPolicy(context.Context, *GetAccountPolicyRequest) (*AccountPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountPolicy not implemented")
+}
+func (UnimplementedBankServiceServer) UpdateAccountPolicy(context.Context, *UpdateAccountPolicyRequest) (*AccountPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAccountPolicy not implemented")
+}
 func (UnimplementedBankServiceServer) mustEmbedUnimplementedBankServiceServer() {}
 
 // UnsafeBankServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -313,6 +373,78 @@ func _BankService_CreateAccount_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BankService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_UpdateAccountStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).UpdateAccountStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_UpdateAccountStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).UpdateAccountStatus(ctx, req.(*UpdateAccountStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_GetAccountPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).GetAccountPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_GetAccountPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).GetAccountPolicy(ctx, req.(*GetAccountPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankService_UpdateAccountPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankServiceServer).UpdateAccountPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankService_UpdateAccountPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankServiceServer).UpdateAccountPolicy(ctx, req.(*UpdateAccountPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BankService_ServiceDesc is the grpc.ServiceDesc for BankService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -328,6 +460,22 @@ var BankService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateAccount",
 			Handler:    _BankService_CreateAccount_Handler,
 		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _BankService_ListTransactions_Handler,
+		},
+		{
+			MethodName: "UpdateAccountStatus",
+			Handler:    _BankService_UpdateAccountStatus_Handler,
+		},
+		{
+			MethodName: "GetAccountPolicy",
+			Handler:    _BankService_GetAccountPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateAccountPolicy",
+			Handler:    _BankService_UpdateAccountPolicy_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	myconfig "github.com/timpamungkas/my-grpc-config"
+	mytls "github.com/timpamungkas/my-grpc-tls"
+	mytracing "github.com/timpamungkas/my-grpc-tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	envPrefix         = "GRPC_GATEWAY"
+	defaultConfigFile = "config.yml"
+)
+
+type config struct {
+	GrpcServerEndpoint string             `yaml:"grpc_server_endpoint" usage:"gRPC server endpoint"`
+	HttpAddress        string             `yaml:"http_address" usage:"HTTP address for the REST gateway"`
+	OpenapiFile        string             `yaml:"openapi_file" usage:"OpenAPI (swagger) file to serve"`
+	HealthPath         string             `yaml:"health_path" usage:"HTTP path reporting the gRPC server health"`
+	ForwardHeaders     []string           `yaml:"forward_headers" usage:"comma separated HTTP headers forwarded as gRPC metadata"`
+	HttpTls            mytls.ServerConfig `yaml:"http_tls"`
+	GrpcTls            mytls.ClientConfig `yaml:"grpc_tls"`
+	Tracing            mytracing.Config   `yaml:"tracing"`
+}
+
+func defaultConfig() config {
+	return config{
+		GrpcServerEndpoint: "localhost:9090",
+		HttpAddress:        ":8081",
+		OpenapiFile:        "../my-grpc-proto/protogen/gateway/openapiv2/merged.swagger.yaml",
+		HealthPath:         "/healthz",
+		ForwardHeaders:     []string{"X-Request-Id", "X-Correlation-Id", "Idempotency-Key"},
+		GrpcTls: mytls.ClientConfig{
+			CaFile: "ssl/ca.crt",
+		},
+		Tracing: mytracing.DefaultConfig(),
+	}
+}
+
+// loadConfig returns the default configuration overridden by config.yml (or the -config file),
+// the GRPC_GATEWAY_* environment variables and command-line flags. It shares flag.CommandLine
+// with glog flags.
+func loadConfig() (config, error) {
+	cfg := defaultConfig()
+
+	err := myconfig.Load(&cfg, myconfig.Options{
+		EnvPrefix:   envPrefix,
+		DefaultFile: defaultConfigFile,
+	})
+
+	return cfg, err
+}
+
+func (c *config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.GrpcServerEndpoint); err != nil {
+		return fmt.Errorf("grpc_server_endpoint %q : %v", c.GrpcServerEndpoint, err)
+	}
+
+	if _, _, err := net.SplitHostPort(c.HttpAddress); err != nil {
+		return fmt.Errorf("http_address %q : %v", c.HttpAddress, err)
+	}
+
+	if _, err := os.Stat(c.OpenapiFile); err != nil {
+		return fmt.Errorf("openapi_file : %v", err)
+	}
+
+	if !strings.HasPrefix(c.HealthPath, "/") || c.HealthPath == docsPath {
+		return fmt.Errorf("health_path %q must be an absolute path other than %v", c.HealthPath, docsPath)
+	}
+
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.HttpTls.Validate(); err != nil {
+		return fmt.Errorf("http_tls : %v", err)
+	}
+
+	if err := c.GrpcTls.Validate(); err != nil {
+		return fmt.Errorf("grpc_tls : %v", err)
+	}
+
+	return nil
+}
+
+// dialOptions returns the transport credentials matching the TLS settings, and the tracing
+// interceptors propagating the HTTP request trace to the gRPC server.
+func (c config) dialOptions() ([]grpc.DialOption, error) {
+	opts := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	}
+
+	if !c.GrpcTls.Enabled {
+		return append(opts, grpc.WithTransportCredentials(insecure.NewCredentials())), nil
+	}
+
+	tlsConfig, err := mytls.NewClientTLSConfig(c.GrpcTls)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create gRPC client credentials : %v", err)
+	}
+
+	return append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const healthCheckTimeout = 3 * time.Second
+
+type healthResponse struct {
+	Service string `json:"service,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// serveHealth proxies the gRPC health status of the service given in the service query
+// parameter, or of the whole server when empty. It answers 200 when SERVING, 404 for an
+// unknown service and 503 otherwise, including when the gRPC server can't be reached.
+func serveHealth(conn *grpc.ClientConn) http.HandlerFunc {
+	client := healthpb.NewHealthClient(conn)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+
+		service := r.URL.Query().Get("service")
+		res := healthResponse{Service: service}
+		httpStatus := http.StatusServiceUnavailable
+
+		check, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+
+		switch {
+		case err == nil:
+			res.Status = check.Status.String()
+
+			if check.Status == healthpb.HealthCheckResponse_SERVING {
+				httpStatus = http.StatusOK
+			}
+		case status.Code(err) == codes.NotFound:
+			res.Status = healthpb.HealthCheckResponse_SERVICE_UNKNOWN.String()
+			httpStatus = http.StatusNotFound
+		default:
+			glog.Warningf("gRPC health check of %q failed : %v", service, err)
+			res.Status = healthpb.HealthCheckResponse_UNKNOWN.String()
+			res.Error = status.Convert(err).Message()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(httpStatus)
+		json.NewEncoder(w).Encode(res)
+	}
+}
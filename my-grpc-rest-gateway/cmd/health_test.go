@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startHealthServer serves hs on an in-memory listener until the test ends, and returns a
+// connection to it.
+func startHealthServer(t *testing.T, hs *health.Server) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, hs)
+
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatalf("Dial : %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
+func TestServeHealth(t *testing.T) {
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("bank.BankService", healthpb.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus("hello.HelloService", healthpb.HealthCheckResponse_SERVING)
+	conn := startHealthServer(t, hs)
+
+	// the connection of a server already stopped
+	down := health.NewServer()
+	downConn := startHealthServer(t, down)
+	down.Shutdown()
+
+	tests := []struct {
+		name       string
+		conn       *grpc.ClientConn
+		service    string
+		wantCode   int
+		wantStatus string
+	}{
+		{"server serving", conn, "", http.StatusOK, "SERVING"},
+		{"service serving", conn, "hello.HelloService", http.StatusOK, "SERVING"},
+		{"service not serving", conn, "bank.BankService", http.StatusServiceUnavailable, "NOT_SERVING"},
+		{"unknown service", conn, "other.Service", http.StatusNotFound, "SERVICE_UNKNOWN"},
+		{"server shutting down", downConn, "", http.StatusServiceUnavailable, "NOT_SERVING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			serveHealth(tt.conn)(w, httptest.NewRequest(http.MethodGet, "/healthz?service="+tt.service, nil))
+
+			var res healthResponse
+
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatalf("decode response : %v", err)
+			}
+
+			if w.Code != tt.wantCode || res.Status != tt.wantStatus || res.Service != tt.service {
+				t.Errorf("healthz = %v %+v, want %v with status %v of %q", w.Code, res, tt.wantCode,
+					tt.wantStatus, tt.service)
+			}
+
+			if got := w.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", got)
+			}
+		})
+	}
+}
+
+func TestServeHealthUnreachable(t *testing.T) {
+	conn := startHealthServer(t, health.NewServer())
+	conn.Close()
+
+	w := httptest.NewRecorder()
+	serveHealth(conn)(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var res healthResponse
+
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response : %v", err)
+	}
+
+	if w.Code != http.StatusServiceUnavailable || res.Status != "UNKNOWN" || res.Error == "" {
+		t.Errorf("healthz = %v %+v, want %v with status UNKNOWN and the error", w.Code, res,
+			http.StatusServiceUnavailable)
+	}
+}
@@ -1,20 +1,160 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	bankgw "github.com/timpamungkas/my-grpc-proto/protogen/gateway/go/proto/bank"
+	hellogw "github.com/timpamungkas/my-grpc-proto/protogen/gateway/go/proto/hello"
+	reslgw "github.com/timpamungkas/my-grpc-proto/protogen/gateway/go/proto/resiliency"
+
+	myconfig "github.com/timpamungkas/my-grpc-config"
+	mytls "github.com/timpamungkas/my-grpc-tls"
+	mytracing "github.com/timpamungkas/my-grpc-tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+const (
+	docsPath    = "/docs/merged.swagger.yaml"
+	serviceName = "my-grpc-rest-gateway"
+)
+
+type registerHandlerFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+func headerMatcher(headers []string) runtime.HeaderMatcherFunc {
+	forwarded := map[string]bool{}
+
+	for _, h := range headers {
+		if h = strings.TrimSpace(h); h != "" {
+			forwarded[textproto.CanonicalMIMEHeaderKey(h)] = true
+		}
+	}
+
+	return func(key string) (string, bool) {
+		if forwarded[textproto.CanonicalMIMEHeaderKey(key)] {
+			return strings.ToLower(key), true
+		}
+
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+func serveOpenAPI(openapiFile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		http.ServeFile(w, r, openapiFile)
+	}
+}
+
+func run(cfg config) error {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Register gRPC server endpoint
+	// Note: Make sure the gRPC server is running properly and accessible
+	gwmux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher(cfg.ForwardHeaders)),
+	)
+	opts, err := cfg.dialOptions()
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.DialContext(ctx, cfg.GrpcServerEndpoint, opts...)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	handlers := []registerHandlerFunc{
+		hellogw.RegisterHelloServiceHandler,
+		bankgw.RegisterBankServiceHandler,
+		reslgw.RegisterResiliencyServiceHandler,
+		reslgw.RegisterResiliencyWithMetadataServiceHandler,
+	}
+
+	for _, register := range handlers {
+		if err := register(ctx, gwmux, conn); err != nil {
+			return err
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(docsPath, serveOpenAPI(cfg.OpenapiFile))
+	mux.HandleFunc(cfg.HealthPath, serveHealth(conn))
+	mux.Handle("/", gwmux)
+
+	server := &http.Server{
+		Addr: cfg.HttpAddress,
+		Handler: otelhttp.NewHandler(mux, "gateway",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + r.URL.Path
+			}),
+			otelhttp.WithFilter(func(r *http.Request) bool {
+				return r.URL.Path != cfg.HealthPath
+			}),
+		),
+	}
+
+	glog.Infof("REST gateway listening on %v (TLS %v), proxying to gRPC server %v", cfg.HttpAddress,
+		cfg.HttpTls.Enabled, cfg.GrpcServerEndpoint)
+
+	// Start HTTP server (and proxy calls to gRPC server endpoint)
+	if !cfg.HttpTls.Enabled {
+		return server.ListenAndServe()
+	}
+
+	tlsConfig, err := mytls.NewServerTLSConfig(cfg.HttpTls, "h2", "http/1.1")
+
+	if err != nil {
+		return fmt.Errorf("can't create HTTP server credentials : %v", err)
+	}
+
+	server.TLSConfig = tlsConfig
+
+	// certificates come from the TLS configuration, reloaded when they change
+	return server.ListenAndServeTLS("", "")
+}
+
 func main() {
-	entries, err := os.ReadDir("D:/my-courses/protobuf-grpc-go")
+	defer glog.Flush()
+
+	cfg, err := loadConfig()
+
 	if err != nil {
-		log.Fatal(err)
+		glog.Fatal(err)
 	}
 
-	for _, e := range entries {
-		if e.IsDir() {
-			fmt.Println(e.Name())
+	glog.Infof("Effective configuration :\n%v", myconfig.Redacted(cfg))
+
+	shutdownTracing, err := mytracing.Setup(context.Background(), serviceName, cfg.Tracing)
+
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			glog.Warningf("Can't flush traces : %v", err)
 		}
+	}()
+
+	if err := run(cfg); err != nil {
+		glog.Fatal(err)
 	}
 }
@@ -0,0 +1,171 @@
+// Package certgen generates throwaway CAs and certificates, for integration tests and local
+// runs. Keys are ECDSA P-256, certificates are valid for both server and client authentication.
+package certgen
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"time"
+)
+
+const defaultValidity = 24 * time.Hour
+
+type CA struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// Options describe an issued certificate. Validity defaults to a day.
+type Options struct {
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+	// URIs are e.g. SPIFFE ids, the first one identifies mutual TLS clients
+	URIs     []*url.URL
+	Validity time.Duration
+}
+
+type Certificate struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// NewCA returns a self-signed CA valid for validity, a day when zero.
+func NewCA(commonName string, validity time.Duration) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't generate CA key : %v", err)
+	}
+
+	template, err := newTemplate(commonName, validity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create CA certificate : %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+	}, nil
+}
+
+// Issue returns a certificate signed by ca.
+func (ca *CA) Issue(opts Options) (*Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't generate key : %v", err)
+	}
+
+	template, err := newTemplate(opts.CommonName, opts.Validity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	template.DNSNames = opts.DNSNames
+	template.IPAddresses = opts.IPAddresses
+	template.URIs = opts.URIs
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, &key.PublicKey, ca.key)
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create certificate : %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Certificate{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}),
+	}, nil
+}
+
+// WriteFile writes the CA certificate, e.g. as the CA bundle of clients.
+func (ca *CA) WriteFile(certFile string) error {
+	return os.WriteFile(certFile, ca.CertPEM, 0o644)
+}
+
+// WriteFiles writes the certificate and its key. The files are replaced by renaming, so a
+// program reloading them never reads a partial file.
+func (c *Certificate) WriteFiles(certFile string, keyFile string) error {
+	if err := writeFileAtomic(keyFile, c.KeyPEM, 0o600); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(certFile, c.CertPEM, 0o644)
+}
+
+func newTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't generate serial number : %v", err)
+	}
+
+	if validity == 0 {
+		validity = defaultValidity
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		// tolerates clocks slightly behind
+		NotBefore: now.Add(-time.Minute),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+func writeFileAtomic(file string, data []byte, perm os.FileMode) error {
+	tmp := file + ".tmp"
+
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
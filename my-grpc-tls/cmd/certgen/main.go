@@ -0,0 +1,81 @@
+// Command certgen writes a throwaway CA, a server certificate and a client certificate, for
+// local runs over TLS and mutual TLS :
+//
+//	go run ./cmd/certgen -dir ../my-grpc-go-server/ssl
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-tls/certgen"
+)
+
+func main() {
+	dir := flag.String("dir", "ssl", "directory receiving ca.crt, server.crt, server.pem, client.crt and client.pem")
+	hosts := flag.String("hosts", "localhost,127.0.0.1", "comma separated server DNS names and IP addresses")
+	client := flag.String("client", "client", "client certificate common name, the subject of mutual TLS callers")
+	validity := flag.Duration("validity", 30*24*time.Hour, "certificates validity")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatalln("Can't create directory :", err)
+	}
+
+	ca, err := certgen.NewCA("my-grpc test CA", *validity)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	serverOpts := certgen.Options{
+		CommonName: "my-grpc-go-server",
+		Validity:   *validity,
+	}
+
+	for _, h := range strings.Split(*hosts, ",") {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+
+		if ip := net.ParseIP(h); ip != nil {
+			serverOpts.IPAddresses = append(serverOpts.IPAddresses, ip)
+		} else {
+			serverOpts.DNSNames = append(serverOpts.DNSNames, h)
+		}
+	}
+
+	server, err := ca.Issue(serverOpts)
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	clientCert, err := ca.Issue(certgen.Options{
+		CommonName: *client,
+		Validity:   *validity,
+	})
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := ca.WriteFile(filepath.Join(*dir, "ca.crt")); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := server.WriteFiles(filepath.Join(*dir, "server.crt"), filepath.Join(*dir, "server.pem")); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := clientCert.WriteFiles(filepath.Join(*dir, "client.crt"), filepath.Join(*dir, "client.pem")); err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf("Certificates written to %v\n", *dir)
+}
@@ -0,0 +1,3 @@
+module github.com/timpamungkas/my-grpc-tls
+
+go 1.20
@@ -0,0 +1,108 @@
+package tlsconfig
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloadCheckInterval limits how often the files are checked for changes, handshakes in
+// between use the cached value.
+var reloadCheckInterval = time.Second
+
+// reloader holds a value loaded from files, e.g. a certificate and its key, and loads it again
+// when one of the files changed. A failed reload is logged and the previous value kept, so a
+// rotation written file by file doesn't break the handshakes in between.
+type reloader[T any] struct {
+	files    []string
+	load     func() (T, error)
+	mu       sync.Mutex
+	checked  time.Time
+	modTimes []time.Time
+	value    T
+}
+
+func newReloader[T any](load func() (T, error), files ...string) (*reloader[T], error) {
+	r := &reloader[T]{
+		files: files,
+		load:  load,
+	}
+
+	value, err := load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.value = value
+	r.modTimes, _ = r.stat()
+	r.checked = time.Now()
+
+	return r, nil
+}
+
+func (r *reloader[T]) get() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < reloadCheckInterval {
+		return r.value
+	}
+
+	r.checked = time.Now()
+	modTimes, err := r.stat()
+
+	if err != nil {
+		log.Println("Can't check TLS files for changes :", err)
+		return r.value
+	}
+
+	if !r.changed(modTimes) {
+		return r.value
+	}
+
+	// recorded even on failure, the next change of the files triggers the next attempt
+	r.modTimes = modTimes
+	value, err := r.load()
+
+	if err != nil {
+		log.Printf("Can't reload %v, keeping the previous one : %v\n", r.files, err)
+		return r.value
+	}
+
+	log.Printf("Reloaded %v\n", r.files)
+	r.value = value
+
+	return r.value
+}
+
+func (r *reloader[T]) stat() ([]time.Time, error) {
+	modTimes := make([]time.Time, len(r.files))
+
+	for i, file := range r.files {
+		info, err := os.Stat(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes[i] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+func (r *reloader[T]) changed(modTimes []time.Time) bool {
+	if len(modTimes) != len(r.modTimes) {
+		return true
+	}
+
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,291 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timpamungkas/my-grpc-tls/certgen"
+)
+
+// testPki writes a CA, a server certificate and a client certificate issued by it under a
+// temporary directory.
+type testPki struct {
+	dir    string
+	ca     *certgen.CA
+	server ServerConfig
+	client ClientConfig
+}
+
+func newTestPki(t *testing.T) *testPki {
+	t.Helper()
+
+	// every handshake checks the files for changes
+	interval := reloadCheckInterval
+	reloadCheckInterval = 0
+
+	t.Cleanup(func() {
+		reloadCheckInterval = interval
+	})
+
+	dir := t.TempDir()
+	p := &testPki{
+		dir: dir,
+		ca:  newTestCA(t, "Test CA"),
+		server: ServerConfig{
+			Enabled:           true,
+			CertFile:          filepath.Join(dir, "server.pem"),
+			KeyFile:           filepath.Join(dir, "server.key"),
+			ClientCaFile:      filepath.Join(dir, "client-ca.pem"),
+			RequireClientCert: true,
+		},
+		client: ClientConfig{
+			Enabled:            true,
+			CaFile:             filepath.Join(dir, "server-ca.pem"),
+			ServerNameOverride: "localhost",
+			CertFile:           filepath.Join(dir, "client.pem"),
+			KeyFile:            filepath.Join(dir, "client.key"),
+		},
+	}
+
+	for _, file := range []string{p.server.ClientCaFile, p.client.CaFile} {
+		if err := p.ca.WriteFile(file); err != nil {
+			t.Fatalf("WriteFile : %v", err)
+		}
+	}
+
+	p.issue(t, p.ca, "server", p.server.CertFile, p.server.KeyFile)
+	p.issue(t, p.ca, "client", p.client.CertFile, p.client.KeyFile)
+
+	return p
+}
+
+func newTestCA(t *testing.T, name string) *certgen.CA {
+	t.Helper()
+
+	ca, err := certgen.NewCA(name, time.Hour)
+
+	if err != nil {
+		t.Fatalf("NewCA : %v", err)
+	}
+
+	return ca
+}
+
+func (p *testPki) issue(t *testing.T, ca *certgen.CA, name string, certFile string, keyFile string) {
+	t.Helper()
+
+	cert, err := ca.Issue(certgen.Options{CommonName: name, DNSNames: []string{"localhost"}})
+
+	if err != nil {
+		t.Fatalf("Issue : %v", err)
+	}
+
+	if err := cert.WriteFiles(certFile, keyFile); err != nil {
+		t.Fatalf("WriteFiles : %v", err)
+	}
+}
+
+// handshake runs a TLS handshake between server and client over an in-memory connection, and
+// returns the peer certificate common names seen by each side, or the client error when the
+// handshake fails.
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) (string, string, error) {
+	t.Helper()
+
+	// a loopback connection, unlike net.Pipe, buffers the writes of a side not read yet
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("Listen : %v", err)
+	}
+
+	defer lis.Close()
+
+	clientConn, err := net.Dial("tcp", lis.Addr().String())
+
+	if err != nil {
+		t.Fatalf("Dial : %v", err)
+	}
+
+	defer clientConn.Close()
+
+	serverConn, err := lis.Accept()
+
+	if err != nil {
+		t.Fatalf("Accept : %v", err)
+	}
+
+	defer serverConn.Close()
+
+	serverDone := make(chan string, 1)
+
+	go func() {
+		conn := tls.Server(serverConn, server)
+
+		if err := conn.Handshake(); err != nil {
+			serverConn.Close()
+			serverDone <- ""
+			return
+		}
+
+		serverDone <- conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}()
+
+	conn := tls.Client(clientConn, client)
+	err = conn.Handshake()
+
+	if err != nil {
+		clientConn.Close()
+		<-serverDone
+		return "", "", err
+	}
+
+	// the server verifies the client certificate after the client is done
+	clientSeen := conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	serverSeen := <-serverDone
+
+	if serverSeen == "" {
+		_, err = conn.Read(make([]byte, 1))
+	}
+
+	return serverSeen, clientSeen, err
+}
+
+func newConfigs(t *testing.T, p *testPki) (*tls.Config, *tls.Config) {
+	t.Helper()
+
+	server, err := NewServerTLSConfig(p.server)
+
+	if err != nil {
+		t.Fatalf("NewServerTLSConfig : %v", err)
+	}
+
+	client, err := NewClientTLSConfig(p.client)
+
+	if err != nil {
+		t.Fatalf("NewClientTLSConfig : %v", err)
+	}
+
+	return server, client
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	p := newTestPki(t)
+	server, client := newConfigs(t, p)
+
+	serverSeen, clientSeen, err := handshake(t, server, client)
+
+	if err != nil {
+		t.Fatalf("handshake : %v", err)
+	}
+
+	if serverSeen != "client" || clientSeen != "server" {
+		t.Errorf("server saw %q, client saw %q, want client and server", serverSeen, clientSeen)
+	}
+}
+
+func TestUntrustedPeers(t *testing.T) {
+	tests := []struct {
+		name string
+		// untrust replaces a certificate with one of another CA
+		untrust func(t *testing.T, p *testPki, other *certgen.CA)
+	}{
+		{"untrusted client", func(t *testing.T, p *testPki, other *certgen.CA) {
+			p.issue(t, other, "client", p.client.CertFile, p.client.KeyFile)
+		}},
+		{"untrusted server", func(t *testing.T, p *testPki, other *certgen.CA) {
+			p.issue(t, other, "server", p.server.CertFile, p.server.KeyFile)
+		}},
+		{"client without certificate", func(t *testing.T, p *testPki, other *certgen.CA) {
+			p.client.CertFile, p.client.KeyFile = "", ""
+		}},
+		{"wrong server name", func(t *testing.T, p *testPki, other *certgen.CA) {
+			p.client.ServerNameOverride = "bank.example.com"
+		}},
+		// e.g. an IP address target, which sends no server name
+		{"no server name", func(t *testing.T, p *testPki, other *certgen.CA) {
+			p.client.ServerNameOverride = ""
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPki(t)
+			tt.untrust(t, p, newTestCA(t, "Other CA"))
+			server, client := newConfigs(t, p)
+
+			if _, _, err := handshake(t, server, client); err == nil {
+				t.Errorf("handshake succeeded, want it refused")
+			}
+		})
+	}
+}
+
+func TestRotatedFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		// rotate replaces files on disk, once the configurations are built
+		rotate func(t *testing.T, p *testPki)
+		// broken tells whether the handshake fails between the rotation and fixed
+		broken bool
+		fixed  func(t *testing.T, p *testPki)
+		// want is the client name seen by the server, and the server name seen by the client
+		want string
+	}{
+		{"server certificate", func(t *testing.T, p *testPki) {
+			p.issue(t, p.ca, "rotated server", p.server.CertFile, p.server.KeyFile)
+		}, false, nil, "client/rotated server"},
+		{"client certificate", func(t *testing.T, p *testPki) {
+			p.issue(t, p.ca, "rotated client", p.client.CertFile, p.client.KeyFile)
+		}, false, nil, "rotated client/server"},
+		{"server CA", func(t *testing.T, p *testPki) {
+			p.ca = newTestCA(t, "Rotated CA")
+			p.issue(t, p.ca, "rotated server", p.server.CertFile, p.server.KeyFile)
+		}, true, func(t *testing.T, p *testPki) {
+			if err := p.ca.WriteFile(p.client.CaFile); err != nil {
+				t.Fatalf("WriteFile : %v", err)
+			}
+		}, "client/rotated server"},
+		{"client CA", func(t *testing.T, p *testPki) {
+			p.ca = newTestCA(t, "Rotated CA")
+			p.issue(t, p.ca, "rotated client", p.client.CertFile, p.client.KeyFile)
+		}, true, func(t *testing.T, p *testPki) {
+			if err := p.ca.WriteFile(p.server.ClientCaFile); err != nil {
+				t.Fatalf("WriteFile : %v", err)
+			}
+		}, "rotated client/server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPki(t)
+			server, client := newConfigs(t, p)
+
+			if _, _, err := handshake(t, server, client); err != nil {
+				t.Fatalf("handshake before rotation : %v", err)
+			}
+
+			tt.rotate(t, p)
+
+			if tt.broken {
+				if _, _, err := handshake(t, server, client); err == nil {
+					t.Fatalf("handshake succeeded with the CA bundle not rotated yet")
+				}
+
+				tt.fixed(t, p)
+			}
+
+			serverSeen, clientSeen, err := handshake(t, server, client)
+
+			if err != nil {
+				t.Fatalf("handshake after rotation : %v", err)
+			}
+
+			if got := serverSeen + "/" + clientSeen; got != tt.want {
+				t.Errorf("server/client saw %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
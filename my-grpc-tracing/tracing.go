@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Config is embedded in the configuration of each program, under tracing.
+type Config struct {
+	Exporter    string  `yaml:"exporter" usage:"trace exporter (none, otlp, stdout or file)"`
+	Endpoint    string  `yaml:"endpoint" usage:"OTLP gRPC collector endpoint (host:port), for otlp exporter"`
+	Insecure    bool    `yaml:"insecure" usage:"connect to the OTLP collector without TLS"`
+	File        string  `yaml:"file" usage:"file receiving spans as JSON, for file exporter"`
+	SampleRatio float64 `yaml:"sample_ratio" usage:"ratio of new traces sampled, between 0 and 1"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		Endpoint:    "localhost:4317",
+		Insecure:    true,
+		File:        "traces.json",
+		SampleRatio: 1,
+	}
+}
+
+func (c *Config) Validate() error {
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout:
+	case ExporterOtlp:
+		if c.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint is required for %v exporter", c.Exporter)
+		}
+	case ExporterFile:
+		if c.File == "" {
+			return fmt.Errorf("tracing.file is required for %v exporter", c.Exporter)
+		}
+	default:
+		return fmt.Errorf("unknown tracing.exporter %v, use %v, %v, %v or %v", c.Exporter,
+			ExporterNone, ExporterOtlp, ExporterStdout, ExporterFile)
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio %v must be between 0 and 1", c.SampleRatio)
+	}
+
+	return nil
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter is none, a global
+// tracer provider exporting the spans of serviceName. A sampled parent is always followed, so
+// a trace started by the client stays complete across the gateway and the server.
+//
+// The returned function flushes the pending spans and must be called before exiting.
+func Setup(ctx context.Context, serviceName string, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("can't create tracing resource : %v", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		return err
+	}, nil
+}
+
+// newExporter returns the span exporter of cfg, and the file to close after it for the file
+// exporter.
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOtlp:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't create OTLP trace exporter : %v", err)
+		}
+
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't create stdout trace exporter : %v", err)
+		}
+
+		return exporter, nil, nil
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't open trace file : %v", err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("can't create file trace exporter : %v", err)
+		}
+
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %v", cfg.Exporter)
+	}
+}